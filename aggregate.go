@@ -0,0 +1,331 @@
+package gdal
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"github.com/dirac-lee/gdal/gutil/greflect"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// groupCountAlias the alias of count selected by GroupCount
+const groupCountAlias = "gdal_count"
+
+// aggregateTagRegexp the form of tag sql_agg, such as `sum(balance)`, `count(*)` and `count(distinct company_id)`.
+var aggregateTagRegexp = regexp.MustCompile(`^(?i)(count|sum|avg|min|max)\(\s*(distinct\s+)?(\*|[a-zA-Z_][a-zA-Z0-9_]*)\s*\)$`)
+
+// GroupCountRow the count of records of a group by GroupCount
+type GroupCountRow struct {
+	Group map[string]any // values of the group columns
+	Count int64
+}
+
+// Sum the sum of column of records by condition.
+//
+// 💡 HINT: T is the type to receive the sum, such as int64 and float64. Zero returns if no record found.
+//
+// 🚀 example:
+//
+//	total, err := gdal.Sum[int64](ctx, userDAL, &model.UserWhere{CompanyID: gptr.Of[int64](1)}, "balance")
+//
+// SQL:
+// SELECT SUM(`balance`) AS `value` FROM `user` WHERE `company_id` = 1 AND `is_deleted` = false
+func Sum[T any, PO schema.Tabler, Where any, Update any](ctx context.Context, gdal *GDAL[PO, Where, Update], where *Where, column string, options ...QueryOption) (T, error) {
+	return aggregateValue[T](ctx, gdal, where, "SUM", column, options)
+}
+
+// Max the max value of column of records by condition.
+//
+// 💡 HINT: ref Sum.
+//
+// 🚀 example:
+//
+//	maxAge, err := gdal.Max[uint](ctx, userDAL, where, "age")
+//
+// SQL:
+// SELECT MAX(`age`) AS `value` FROM `user` WHERE ...
+func Max[T any, PO schema.Tabler, Where any, Update any](ctx context.Context, gdal *GDAL[PO, Where, Update], where *Where, column string, options ...QueryOption) (T, error) {
+	return aggregateValue[T](ctx, gdal, where, "MAX", column, options)
+}
+
+// Min the min value of column of records by condition.
+//
+// 💡 HINT: ref Sum.
+//
+// 🚀 example:
+//
+//	minAge, err := gdal.Min[uint](ctx, userDAL, where, "age")
+//
+// SQL:
+// SELECT MIN(`age`) AS `value` FROM `user` WHERE ...
+func Min[T any, PO schema.Tabler, Where any, Update any](ctx context.Context, gdal *GDAL[PO, Where, Update], where *Where, column string, options ...QueryOption) (T, error) {
+	return aggregateValue[T](ctx, gdal, where, "MIN", column, options)
+}
+
+// aggregateValue the single aggregate of column, zero if NULL.
+func aggregateValue[T any, PO schema.Tabler, Where any, Update any](ctx context.Context, gdal *GDAL[PO, Where, Update], where *Where, fn string, column string, options []QueryOption) (T, error) {
+	var value *T
+	var zero T
+	if err := gdal.checkColumns(column); err != nil {
+		return zero, err
+	}
+	err := gdal.aggregate(ctx, where, &value, nil, []clause.Expression{aggregateExpr{fn: fn, column: column, alias: "value"}}, options)
+	if err != nil || value == nil {
+		return zero, err
+	}
+	return *value, nil
+}
+
+// Avg the average of column of records by condition.
+//
+// 💡 HINT: zero returns if no record found.
+//
+// 🚀 example:
+//
+//	avgAge, err := userDAL.Avg(ctx, where, "age")
+//
+// SQL:
+// SELECT AVG(`age`) AS `value` FROM `user` WHERE ...
+func (gdal *GDAL[PO, Where, Update]) Avg(ctx context.Context, where *Where, column string, options ...QueryOption) (float64, error) {
+	return aggregateValue[float64](ctx, gdal, where, "AVG", column, options)
+}
+
+// GroupCount count records by condition for each group of groupColumns.
+//
+// 💡 HINT: WithOrder and WithLimit apply to the groups, and the count can be ordered by `gdal_count`.
+//
+// 🚀 example:
+//
+//	rows, err := userDAL.GroupCount(ctx, where, []string{"company_id"}, gdal.WithOrder("gdal_count desc"))
+//	for _, row := range rows {
+//		fmt.Println(row.Group["company_id"], row.Count)
+//	}
+//
+// SQL:
+// SELECT `company_id`,COUNT(*) AS `gdal_count` FROM `user` WHERE ... GROUP BY `company_id` ORDER BY gdal_count desc
+func (gdal *GDAL[PO, Where, Update]) GroupCount(ctx context.Context, where *Where, groupColumns []string, options ...QueryOption) ([]*GroupCountRow, error) {
+	if len(groupColumns) == 0 {
+		return nil, gerror.GDALErrorf("group columns of GroupCount must not be empty")
+	}
+	if err := gdal.checkColumns(groupColumns...); err != nil {
+		return nil, err
+	}
+	var results []map[string]any
+	countExpr := aggregateExpr{fn: "COUNT", column: "*", alias: groupCountAlias}
+	if err := gdal.aggregate(ctx, where, &results, groupColumns, []clause.Expression{countExpr}, options); err != nil {
+		return nil, err
+	}
+
+	rows := make([]*GroupCountRow, 0, len(results))
+	for _, result := range results {
+		for column, value := range result {
+			result[column] = indirectValue(value)
+		}
+		count, err := int64Of(result[groupCountAlias])
+		if err != nil {
+			return nil, err
+		}
+		delete(result, groupCountAlias)
+		rows = append(rows, &GroupCountRow{Group: result, Count: count})
+	}
+	return rows, nil
+}
+
+// Aggregate scan the aggregates of records by condition into result.
+//
+// 💡 HINT: result is a pointer to struct, or to slice of struct (pointer) for groups. Fields tagged by `sql_agg`,
+// such as `sql_agg:"sum(balance)"`, receive the aggregates, supporting count, sum, avg, min and max with optional
+// distinct. Other fields tagged by `gorm:"column:{{column_name}}"` are the group columns.
+//
+// ⚠️  WARNING: the aggregate of no record is NULL except count, so use pointer fields to receive them.
+//
+// 🚀 example:
+//
+//	type CompanyStat struct {
+//		CompanyID int64   `gorm:"column:company_id"`
+//		Total     int64   `gorm:"column:total" sql_agg:"count(*)"`
+//		Balance   *int64  `gorm:"column:balance" sql_agg:"sum(balance)"`
+//		AvgAge    float64 `gorm:"column:avg_age" sql_agg:"avg(age)"`
+//	}
+//
+//	var stats []CompanyStat
+//	err := userDAL.Aggregate(ctx, where, &stats, gdal.WithOrder("total desc"))
+//
+// SQL:
+// SELECT `company_id`,COUNT(*) AS `total`,SUM(`balance`) AS `balance`,AVG(`age`) AS `avg_age` FROM `user`
+// WHERE ... GROUP BY `company_id` ORDER BY total desc
+func (gdal *GDAL[PO, Where, Update]) Aggregate(ctx context.Context, where *Where, result any, options ...QueryOption) error {
+	groups, aggregates, err := gdal.parseAggregateResult(result)
+	if err != nil {
+		return err
+	}
+	return gdal.aggregate(ctx, where, result, groups, aggregates, options)
+}
+
+// aggregate scan the aggregates of records by condition into result, grouped by groups if any.
+func (gdal *GDAL[PO, Where, Update]) aggregate(ctx context.Context, where *Where, result any, groups []string, aggregates []clause.Expression, options []QueryOption) error {
+	options, err := gdal.scopeIfSoftDelete(options) // filter out soft-deleted records unless unscoped.
+	if err != nil {
+		return err
+	}
+	injectDefaultIfHas(where)                      // when field is not set in `where`,  insert customized default value  if customer has set it.
+	indexedDAL := gdal.forceIndexIfHas(ctx, where) // force index if  it is set in `where`.
+	options = append(options[:len(options):len(options)], withAggregates(groups, aggregates...))
+	return indexedDAL.extended().Aggregate(ctx, gdal.MakePO(), where, result, options...)
+}
+
+// parseAggregateResult parse the group columns and aggregate expressions from the fields of result.
+func (gdal *GDAL[PO, Where, Update]) parseAggregateResult(result any) ([]string, []clause.Expression, error) {
+	rv := reflect.ValueOf(result)
+	if rv.Kind() != reflect.Ptr {
+		return nil, nil, gerror.GDALErrorf("result of Aggregate must be pointer, but got %v", rv.Type())
+	}
+	rt, err := greflect.GetElemStructType(rv.Type())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var groups []string
+	var aggregates []clause.Expression
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		var column string
+		if gormTag := strings.TrimSpace(field.Tag.Get("gorm")); len(gormTag) > 0 {
+			tagKVs, err := getKVsFromTag(gormTag)
+			if err != nil {
+				return nil, nil, err
+			}
+			column = tagKVs["column"]
+		}
+		tag, isAggregate := field.Tag.Lookup("sql_agg")
+		if !isAggregate {
+			if len(column) > 0 {
+				groups = append(groups, column)
+			}
+			continue
+		}
+
+		if len(column) == 0 {
+			column = gdal.DB().NamingStrategy.ColumnName("", field.Name)
+		}
+		expr, err := parseAggregateTag(field.Name, tag, column)
+		if err != nil {
+			return nil, nil, err
+		}
+		if expr.column != "*" {
+			if err := gdal.checkColumns(expr.column); err != nil {
+				return nil, nil, err
+			}
+		}
+		aggregates = append(aggregates, expr)
+	}
+	if len(aggregates) == 0 {
+		return nil, nil, gerror.GDALErrorf("result (%v) of Aggregate has no field tagged by sql_agg", rt)
+	}
+	if err := gdal.checkColumns(groups...); err != nil {
+		return nil, nil, err
+	}
+	return groups, aggregates, nil
+}
+
+// checkColumns whether columns are all mapped by PO.
+func (gdal *GDAL[PO, Where, Update]) checkColumns(columns ...string) error {
+	meta, err := getPOMeta[PO]()
+	if err != nil {
+		return err
+	}
+	for _, column := range columns {
+		if !meta.hasColumn(column) {
+			return gerror.ColumnNotFoundErr(meta.structType, column)
+		}
+	}
+	return nil
+}
+
+// aggregateExpr the aggregate function of column
+//
+// SQL: FN([DISTINCT ]`column`) AS `alias`
+type aggregateExpr struct {
+	fn       string
+	distinct bool
+	column   string // `*` for all
+	alias    string
+}
+
+// parseAggregateTag parse tag sql_agg of field, such as `sum(balance)`.
+func parseAggregateTag(fieldName string, tag string, alias string) (aggregateExpr, error) {
+	matches := aggregateTagRegexp.FindStringSubmatch(strings.TrimSpace(tag))
+	if matches == nil {
+		return aggregateExpr{}, gerror.GDALErrorf("field (%s) with sql_agg (%s) invalid", fieldName, tag)
+	}
+	expr := aggregateExpr{
+		fn:       strings.ToUpper(matches[1]),
+		distinct: len(matches[2]) > 0,
+		column:   matches[3],
+		alias:    alias,
+	}
+	if expr.column == "*" && (expr.fn != "COUNT" || expr.distinct) {
+		return aggregateExpr{}, gerror.GDALErrorf("field (%s) with sql_agg (%s) invalid", fieldName, tag)
+	}
+	return expr, nil
+}
+
+func (expr aggregateExpr) Build(builder clause.Builder) {
+	builder.WriteString(expr.fn)
+	builder.WriteByte('(')
+	if expr.distinct {
+		builder.WriteString("DISTINCT ")
+	}
+	if expr.column == "*" {
+		builder.WriteByte('*')
+	} else {
+		builder.WriteQuoted(clause.Column{Name: expr.column})
+	}
+	builder.WriteString(") AS ")
+	builder.WriteQuoted(clause.Column{Name: expr.alias})
+}
+
+// indirectValue the value pointed to, nil if nil pointer, as scanned into map by driver.
+func indirectValue(value any) any {
+	rv := reflect.ValueOf(value)
+	for rv.IsValid() && (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	return rv.Interface()
+}
+
+// int64Of the integer scanned by driver, which can be any integer type or text.
+func int64Of(value any) (int64, error) {
+	rv := reflect.ValueOf(value)
+	switch {
+	case !rv.IsValid():
+		return 0, nil
+	case rv.CanInt():
+		return rv.Int(), nil
+	case rv.CanUint():
+		return int64(rv.Uint()), nil
+	case rv.CanFloat():
+		return int64(rv.Float()), nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return strconv.ParseInt(string(v), 10, 64)
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	}
+	return 0, gerror.GDALErrorf("can not convert %T to int64", value)
+}
@@ -0,0 +1,228 @@
+package gdal
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"github.com/dirac-lee/gdal/gutil/gsql"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// mUpdateBatchSize the number of records updated by a statement of MUpdateByIDs
+const mUpdateBatchSize = 100
+
+// MUpdateByIDs updates multiple records by primary key with different values in one statement, and return
+// total rows affected.
+//
+// 💡 HINT: updates is a map from primary key to *Update, such as `map[int64]*model.UserUpdate`. As for composite
+// primary key, the key can be any comparable struct (pointer) whose fields are mapped to the primary key
+// columns, such as *PO. Each column is
+// updated by `CASE id WHEN ... THEN ... ELSE column END`, so that the records without that field set are left
+// unchanged. `sql_expr` updaters such as `+` are supported.
+//
+// 💡 HINT: records are updated in the order of primary key, by batches of 100 in a transaction.
+// Soft-deleted records are not updated unless WithUnscoped, and the version is increased by 1 if PO has
+// version column.
+//
+// ⚠️  WARNING: neither the expected version nor `sql_min` is supported, use UpdateByID instead.
+//
+// 🚀 example:
+//
+//	numUpdated, err := gdal.MUpdateByIDs(ctx, userDAL, map[int64]*model.UserUpdate{
+//		110: {Name: gptr.Of("dirac"), BalanceAdd: gptr.Of[int64](10)},
+//		120: {Name: gptr.Of("bob")},
+//	})
+//
+// SQL:
+// UPDATE `user` SET `balance`=CASE `id` WHEN 110 THEN `user`.`balance` + 10 ELSE `balance` END,
+// `name`=CASE `id` WHEN 110 THEN 'dirac' WHEN 120 THEN 'bob' ELSE `name` END WHERE `id` IN (110,120)
+func MUpdateByIDs[ID comparable, PO schema.Tabler, Where any, Update any](ctx context.Context, gdal *GDAL[PO, Where, Update], updates map[ID]*Update, options ...QueryOption) (int64, error) {
+	meta, err := getPOMeta[PO]()
+	if err != nil {
+		return 0, err
+	}
+	rows, err := buildKeyedUpdates(meta, updates)
+	if err != nil || len(rows) == 0 {
+		return 0, err
+	}
+	options, err = gdal.scopeIfSoftDelete(options) // the soft-deleted records are not to be updated.
+	if err != nil {
+		return 0, err
+	}
+
+	if len(rows) <= mUpdateBatchSize {
+		return gdal.mUpdateByIDs(ctx, meta, rows, options)
+	}
+	var total int64
+	err = Transaction(ctx, gdal.DB(), func(ctx context.Context) error {
+		for begin := 0; begin < len(rows); begin += mUpdateBatchSize {
+			end := begin + mUpdateBatchSize
+			if end > len(rows) {
+				end = len(rows)
+			}
+			rowsAffected, err := gdal.mUpdateByIDs(ctx, meta, rows[begin:end], options)
+			if err != nil {
+				return err
+			}
+			total += rowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// mUpdateByIDs updates a batch of records by CASE expressions.
+func (gdal *GDAL[PO, Where, Update]) mUpdateByIDs(ctx context.Context, meta *poMeta, rows []keyedUpdate, options []QueryOption) (int64, error) {
+	keys := make([]any, 0, len(rows))
+	attrs := make(map[string]any)
+	for _, row := range rows {
+		keys = append(keys, row.key)
+		for column := range row.attrs {
+			if _, ok := attrs[column]; ok {
+				continue
+			}
+			expr := &caseExpr{column: column, primaryKeys: meta.primaryKeys}
+			for _, r := range rows {
+				if value, ok := r.attrs[column]; ok {
+					expr.keys = append(expr.keys, r.keyValues)
+					expr.values = append(expr.values, value)
+				}
+			}
+			attrs[column] = expr
+		}
+	}
+	if len(meta.version) > 0 {
+		attrs[meta.version] = meta.versionIncrExpr()
+	}
+	where, err := gdal.pkWhere(keys...)
+	if err != nil {
+		return 0, err
+	}
+	return gdal.extended().UpdateWithOptions(ctx, gdal.MakePO(), where, attrs, options...)
+}
+
+// keyedUpdate the columns to be updated of record by primary key
+type keyedUpdate struct {
+	key       any
+	keyValues []any // values of primary key columns
+	attrs     map[string]any
+}
+
+// buildKeyedUpdates build updates, a map from primary key to *Update, into keyedUpdate in the order of primary key.
+func buildKeyedUpdates[ID comparable, Update any](meta *poMeta, updates map[ID]*Update) ([]keyedUpdate, error) {
+	rows := make([]keyedUpdate, 0, len(updates))
+	for key, update := range updates {
+		if update == nil {
+			continue
+		}
+		attrs, err := gsql.BuildSQLUpdate(update)
+		if err != nil {
+			return nil, err
+		}
+		if len(attrs) == 0 {
+			continue
+		}
+		if guard, _, err := gsql.BuildSQLUpdateGuard(update); err != nil || guard != nil {
+			if err != nil {
+				return nil, err
+			}
+			return nil, gerror.GDALErrorf("sql_min of model (%v) is not supported by MUpdateByIDs", meta.structType)
+		}
+		if _, ok := attrs[meta.version]; ok && len(meta.version) > 0 {
+			return nil, gerror.GDALErrorf("version column (%s) can not be checked by MUpdateByIDs", meta.version)
+		}
+		keyValues, err := meta.keyValues(key)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, keyedUpdate{key: key, keyValues: keyValues, attrs: attrs})
+	}
+	sort.Slice(rows, func(i, j int) bool { // stable SQL, and locks in the same order
+		return lessValues(rows[i].keyValues, rows[j].keyValues)
+	})
+	return rows, nil
+}
+
+// lessValues compare values of primary key columns one by one.
+func lessValues(a, b []any) bool {
+	for i := range a {
+		if c := compareValue(a[i], b[i]); c != 0 {
+			return c < 0
+		}
+	}
+	return false
+}
+
+// compareValue compare numbers and strings by value, others by text.
+func compareValue(a, b any) int {
+	ra, rb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case ra.CanInt() && rb.CanInt():
+		return compareOrdered(ra.Int(), rb.Int())
+	case ra.CanUint() && rb.CanUint():
+		return compareOrdered(ra.Uint(), rb.Uint())
+	case ra.CanFloat() && rb.CanFloat():
+		return compareOrdered(ra.Float(), rb.Float())
+	case ra.Kind() == reflect.String && rb.Kind() == reflect.String:
+		return compareOrdered(ra.String(), rb.String())
+	}
+	return compareOrdered(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func compareOrdered[T int64 | uint64 | float64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// caseExpr the value of column by primary key of record, and the column itself if not matched.
+//
+// SQL:
+// CASE `id` WHEN ? THEN ? ... ELSE `column` END, or CASE WHEN `a` = ? AND `b` = ? THEN ? ... ELSE `column` END
+// if composite.
+type caseExpr struct {
+	column      string
+	primaryKeys []string
+	keys        [][]any // values of primary key columns
+	values      []any
+}
+
+func (expr *caseExpr) Build(builder clause.Builder) {
+	builder.WriteString("CASE ")
+	if len(expr.primaryKeys) == 1 {
+		builder.WriteQuoted(clause.Column{Name: expr.primaryKeys[0]})
+		builder.WriteByte(' ')
+	}
+	for i, key := range expr.keys {
+		builder.WriteString("WHEN ")
+		if len(expr.primaryKeys) == 1 {
+			builder.AddVar(builder, key[0])
+		} else {
+			for j, column := range expr.primaryKeys {
+				if j > 0 {
+					builder.WriteString(" AND ")
+				}
+				builder.WriteQuoted(clause.Column{Name: column})
+				builder.WriteString(" = ")
+				builder.AddVar(builder, key[j])
+			}
+		}
+		builder.WriteString(" THEN ")
+		builder.AddVar(builder, expr.values[i])
+		builder.WriteByte(' ')
+	}
+	builder.WriteString("ELSE ")
+	builder.WriteQuoted(clause.Column{Name: expr.column})
+	builder.WriteString(" END")
+}
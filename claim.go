@@ -0,0 +1,113 @@
+package gdal
+
+import (
+	"context"
+	"time"
+
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Claim claim at most n records by condition exclusively, update them, and return the claimed records, which
+// is handy for the job queue.
+//
+// 💡 HINT: in a transaction, the records are selected by `FOR UPDATE SKIP LOCKED`, so that concurrent claimers
+// get different records without waiting. Then exactly those records are updated by primary key, such as
+// status, owner and lease expiry, and returned as updated in the order selected. Use WithOrder to claim by
+// priority.
+//
+// ⚠️  WARNING: update must set the columns so that the claimed records no longer match where, otherwise they
+// are claimed again after the transaction ends. `SKIP LOCKED` needs MySQL 8.0 or PostgreSQL 9.5.
+//
+// 🚀 example:
+//
+//	jobs, err := gdal.Claim(ctx, jobDAL, &model.JobWhere{Status: gptr.Of("pending")}, &model.JobUpdate{
+//		Status:          gptr.Of("running"),
+//		Owner:           gsql.NullableOf(workerID),
+//		LeaseExpireTime: gsql.NullableOf(time.Now().Add(time.Minute)),
+//	}, 10, gdal.WithOrder("priority desc, id"))
+//
+// SQL:
+// SELECT ... FROM `job` WHERE `status` = 'pending' ORDER BY priority desc, id LIMIT 10 FOR UPDATE SKIP LOCKED
+// UPDATE `job` SET `status`='running',`owner`='worker-1',`lease_expire_time`=... WHERE `id` IN (1,2,3)
+// SELECT ... FROM `job` WHERE `id` IN (1,2,3)
+func Claim[PO schema.Tabler, Where any, Update any](ctx context.Context, gdal *GDAL[PO, Where, Update], where *Where, update *Update, n int, options ...QueryOption) ([]*PO, error) {
+	if n <= 0 {
+		return nil, gerror.GDALErrorf("number of records to claim must be positive, but got %d", n)
+	}
+	meta, err := getPOMeta[PO]()
+	if err != nil {
+		return nil, err
+	}
+
+	var claimed []*PO
+	err = Transaction(ctx, gdal.DB(), func(ctx context.Context) error {
+		pos, err := gdal.MQuery(ctx, where, append(options[:len(options):len(options)], WithLimit(n), WithSkipLocked())...)
+		if err != nil || len(pos) == 0 {
+			return err
+		}
+		keys := make([]any, 0, len(pos))
+		for _, po := range pos {
+			keys = append(keys, meta.keyOf(po))
+		}
+		keyExpr, err := meta.keyExpr(keys...) // plain expression rather than pkWhere, so that no index hint is needed
+		if err != nil {
+			return err
+		}
+		if _, err = gdal.update(ctx, keyExpr, update, false); err != nil {
+			return err
+		}
+
+		var updated []*PO
+		if err = gdal.Find(ctx, &updated, keyExpr, WithMaster(), WithUnscoped()); err != nil {
+			return err
+		}
+		claimed = orderAs(meta, pos, updated)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// ReleaseExpired update the records by condition whose lease has expired, i.e. leaseColumn is earlier than now,
+// and return the number of records released.
+//
+// 💡 HINT: the companion of Claim, which resets the records claimed by the dead claimers, so that they can be
+// claimed again.
+//
+// ⚠️  WARNING: now is the time of application rather than database. The records whose lease is NULL are
+// not released.
+//
+// 🚀 example:
+//
+//	numReleased, err := gdal.ReleaseExpired(ctx, jobDAL, &model.JobWhere{Status: gptr.Of("running")}, "lease_expire_time",
+//		&model.JobUpdate{Status: gptr.Of("pending"), Owner: gsql.Null[string](), LeaseExpireTime: gsql.Null[time.Time]()})
+//
+// SQL:
+// UPDATE `job` SET `status`='pending',`owner`=NULL,`lease_expire_time`=NULL WHERE `status` = 'running'
+// AND `lease_expire_time` < now
+func ReleaseExpired[PO schema.Tabler, Where any, Update any](ctx context.Context, gdal *GDAL[PO, Where, Update], where *Where, leaseColumn string, update *Update, options ...QueryOption) (int64, error) {
+	if err := gdal.checkColumns(leaseColumn); err != nil {
+		return 0, err
+	}
+	expired := clause.Lt{Column: clause.Column{Name: leaseColumn}, Value: time.Now()}
+	return gdal.MUpdate(ctx, where, update, append(options[:len(options):len(options)], withExprs(expired))...)
+}
+
+// orderAs reorder pos in the order of the records with the same primary keys in selected.
+func orderAs[PO any](meta *poMeta, selected []*PO, pos []*PO) []*PO {
+	poMap := make(map[string]*PO, len(pos))
+	for _, po := range pos {
+		poMap[canonicalKey(meta.poKeyValues(po))] = po
+	}
+	ordered := make([]*PO, 0, len(pos))
+	for _, po := range selected {
+		if found, ok := poMap[canonicalKey(meta.poKeyValues(po))]; ok {
+			ordered = append(ordered, found)
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,218 @@
+package gdal
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	cursorSecretMu sync.RWMutex
+	cursorSecret   []byte // assigned by SetCursorSecret, cursor paging fails until then
+)
+
+// CursorSort sort key of cursor paging, Column must be mapped by PO.
+type CursorSort struct {
+	Column string
+	Desc   bool
+}
+
+// Asc sort by column in ascending order
+func Asc(column string) CursorSort {
+	return CursorSort{Column: column}
+}
+
+// Desc sort by column in descending order
+func Desc(column string) CursorSort {
+	return CursorSort{Column: column, Desc: true}
+}
+
+// SetCursorSecret assign the secret used to sign cursors, so that tampered cursors are rejected.
+//
+// ⚠️  WARNING: cursor paging fails until the secret is assigned, please assign the same secret
+// for all instances of your service, since cursors are passed among them.
+func SetCursorSecret(secret []byte) {
+	cursorSecretMu.Lock()
+	defer cursorSecretMu.Unlock()
+	cursorSecret = append([]byte(nil), secret...)
+}
+
+// checkCursorSecret fail when the secret of cursors is not assigned.
+func checkCursorSecret() error {
+	cursorSecretMu.RLock()
+	defer cursorSecretMu.RUnlock()
+	if len(cursorSecret) == 0 {
+		return gerror.GDALErrorf("cursor secret is not set, call gdal.SetCursorSecret at first")
+	}
+	return nil
+}
+
+func signCursor(payload []byte) []byte {
+	cursorSecretMu.RLock()
+	defer cursorSecretMu.RUnlock()
+	mac := hmac.New(sha256.New, cursorSecret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// cursorPayload the content of cursor
+type cursorPayload struct {
+	Sorts  string            `json:"s"` // signature of sorts, cursor is only valid for the same sorts
+	Values []json.RawMessage `json:"v"` // values of sort columns of the last record
+}
+
+// completeCursorSorts validate sorts by PO, reject nullable sort columns, and append primary key as tie-breaker so that the order is total.
+func completeCursorSorts(meta *poMeta, sorts []CursorSort) ([]CursorSort, error) {
+	if len(sorts) == 0 {
+		return nil, gerror.GDALErrorf("cursor paging needs at least one sort column")
+	}
+	sorted := make(map[string]bool, len(sorts))
+	for _, sort := range sorts {
+		if !meta.hasColumn(sort.Column) {
+			return nil, gerror.ColumnNotFoundErr(meta.structType, sort.Column)
+		}
+		if isNullableType(meta.fieldType(sort.Column)) {
+			return nil, gerror.NullableSortColumnErr(meta.structType, sort.Column)
+		}
+		sorted[sort.Column] = true
+	}
+	completed := make([]CursorSort, 0, len(sorts)+len(meta.primaryKeys))
+	completed = append(completed, sorts...)
+	for _, column := range meta.primaryKeys {
+		if !sorted[column] {
+			completed = append(completed, CursorSort{Column: column, Desc: sorts[len(sorts)-1].Desc})
+		}
+	}
+	return completed, nil
+}
+
+// isNullableType whether field of rt may be NULL, such as *time.Time and sql.NullString.
+//
+// 💡 HINT: predicate such as `birthday > NULL` matches nothing, so that paging would stop at the first NULL.
+func isNullableType(rt reflect.Type) bool {
+	switch rt.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		return true
+	case reflect.Struct:
+		valid, ok := rt.FieldByName("Valid")
+		return ok && valid.Type.Kind() == reflect.Bool
+	default:
+		return false
+	}
+}
+
+// cursorSignature identify table and sorts of cursor paging
+func cursorSignature(table string, sorts []CursorSort) string {
+	var sb strings.Builder
+	sb.WriteString(table)
+	for _, sort := range sorts {
+		sb.WriteString(":")
+		sb.WriteString(sort.Column)
+		if sort.Desc {
+			sb.WriteString(" desc")
+		}
+	}
+	return sb.String()
+}
+
+// cursorOrder build order clause of sorts
+func cursorOrder(sorts []CursorSort) string {
+	orders := make([]string, 0, len(sorts))
+	for _, sort := range sorts {
+		if sort.Desc {
+			orders = append(orders, sort.Column+" DESC")
+		} else {
+			orders = append(orders, sort.Column+" ASC")
+		}
+	}
+	return strings.Join(orders, ", ")
+}
+
+// encodeCursor build cursor by the values of sort columns of po.
+func encodeCursor(meta *poMeta, signature string, sorts []CursorSort, po any) (string, error) {
+	payload := cursorPayload{
+		Sorts:  signature,
+		Values: make([]json.RawMessage, 0, len(sorts)),
+	}
+	for _, sort := range sorts {
+		value, err := json.Marshal(meta.valueOf(po, sort.Column))
+		if err != nil {
+			return "", err
+		}
+		payload.Values = append(payload.Values, value)
+	}
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(content) + "." + base64.RawURLEncoding.EncodeToString(signCursor(content)), nil
+}
+
+// decodeCursor verify cursor, then parse the values of sort columns with the types of PO fields.
+func decodeCursor(meta *poMeta, signature string, sorts []CursorSort, cursor string) ([]any, error) {
+	encodedContent, encodedSign, found := strings.Cut(cursor, ".")
+	if !found {
+		return nil, gerror.InvalidCursorErr(cursor)
+	}
+	content, err := base64.RawURLEncoding.DecodeString(encodedContent)
+	if err != nil {
+		return nil, gerror.InvalidCursorErr(cursor)
+	}
+	sign, err := base64.RawURLEncoding.DecodeString(encodedSign)
+	if err != nil || !hmac.Equal(sign, signCursor(content)) {
+		return nil, gerror.InvalidCursorErr(cursor)
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(content, &payload); err != nil {
+		return nil, gerror.InvalidCursorErr(cursor)
+	}
+	if payload.Sorts != signature || len(payload.Values) != len(sorts) {
+		return nil, gerror.InvalidCursorErr(cursor)
+	}
+	values := make([]any, 0, len(sorts))
+	for i, sort := range sorts {
+		value := reflect.New(meta.fieldType(sort.Column))
+		if err := json.Unmarshal(payload.Values[i], value.Interface()); err != nil {
+			return nil, gerror.InvalidCursorErr(cursor)
+		}
+		values = append(values, value.Elem().Interface())
+	}
+	return values, nil
+}
+
+// buildCursorExpr build the predicate of records after the cursor.
+//
+// 💡 HINT: the predicate is expanded as `create_time < ? OR (create_time = ? AND id < ?)` for all dialects,
+// since row value comparison such as `(create_time, id) < (?, ?)` is unsupported by SQL Server.
+func buildCursorExpr(sorts []CursorSort, values []any) clause.Expression {
+	if len(sorts) == 1 {
+		return cursorCompareExpr(sorts[0], values[0])
+	}
+
+	var orExprs []clause.Expression
+	for i := range sorts {
+		andExprs := make([]clause.Expression, 0, i+1)
+		for j := 0; j < i; j++ {
+			andExprs = append(andExprs, clause.Eq{Column: clause.Column{Name: sorts[j].Column}, Value: values[j]})
+		}
+		andExprs = append(andExprs, cursorCompareExpr(sorts[i], values[i]))
+		orExprs = append(orExprs, clause.And(andExprs...))
+	}
+	return clause.Or(orExprs...)
+}
+
+func cursorCompareExpr(sort CursorSort, value any) clause.Expression {
+	column := clause.Column{Name: sort.Column}
+	if sort.Desc {
+		return clause.Lt{Column: column, Value: value}
+	}
+	return clause.Gt{Column: column, Value: value}
+}
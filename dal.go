@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/dirac-lee/gdal/gutil/gerror"
 	"github.com/dirac-lee/gdal/gutil/gsql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/plugin/dbresolver"
 )
 
@@ -24,15 +27,31 @@ type DAL interface {
 	DB(options ...QueryOption) *gorm.DB
 }
 
+// extendedDAL the operations beyond DAL, on which the extended methods of GDAL are based.
+//
+// 💡 HINT: they are kept out of DAL, so that the DALs implemented elsewhere, such as mocks, are not broken.
+// Those DALs can implement them to support the extended methods, ref GDAL.extended.
+type extendedDAL interface {
+	MCreate(ctx context.Context, pos any, batchSize int) (int64, error)
+	DeleteWithOptions(ctx context.Context, po any, where any, options ...QueryOption) (int64, error)
+	UpdateWithOptions(ctx context.Context, po any, where any, update any, options ...QueryOption) (int64, error)
+	Aggregate(ctx context.Context, po any, where any, result any, options ...QueryOption) error
+	Pluck(ctx context.Context, po any, where any, column string, values any, options ...QueryOption) error
+}
+
 // dal Data Access Layer Instance.
 type dal struct {
-	db *gorm.DB
+	db           *gorm.DB
+	interceptors []Interceptor
 }
 
 // NewDAL new dal.
-func NewDAL(tx *gorm.DB) DAL {
+//
+// 💡 HINT: every operation passes through the global interceptors and then interceptors.
+func NewDAL(tx *gorm.DB, interceptors ...Interceptor) DAL {
 	cli := &dal{
-		db: tx,
+		db:           tx,
+		interceptors: interceptors,
 	}
 	return cli
 }
@@ -41,15 +60,35 @@ func NewDAL(tx *gorm.DB) DAL {
 //
 // 💡 HINT: multiple create records when po is slice.
 func (dal *dal) Create(ctx context.Context, po any) error {
-	db := dal.DBWithCtx(ctx)
+	op := makeOperation(OpCreate, po, nil, nil, nil)
+	return dal.invoke(ctx, op, dal.create)
+}
+
+func (dal *dal) create(ctx context.Context, op *Operation) error {
+	db := dal.dbOf(ctx, op)
 	if db.Error != nil {
 		return db.Error
 	}
 
-	if err := db.Create(po).Error; err != nil {
-		return err
-	}
-	return nil
+	res := db.Create(op.PO)
+	op.RowsAffected = res.RowsAffected
+	return res.Error
+}
+
+// MCreate create records of pos in batches of batchSize, and return success count.
+func (dal *dal) MCreate(ctx context.Context, pos any, batchSize int) (int64, error) {
+	op := makeOperation(OpCreate, pos, nil, nil, nil)
+	err := dal.invoke(ctx, op, func(ctx context.Context, op *Operation) error {
+		db := dal.dbOf(ctx, op)
+		if db.Error != nil {
+			return db.Error
+		}
+
+		res := db.CreateInBatches(op.PO, batchSize)
+		op.RowsAffected = res.RowsAffected
+		return res.Error
+	})
+	return op.RowsAffected, err
 }
 
 // Save insert when there are no conflicts, otherwise update by po's primary key.
@@ -59,63 +98,149 @@ func (dal *dal) Create(ctx context.Context, po any) error {
 // ⚠️  WARNING: po must be a complete object, because Save will save all fields
 // event though the field is zero value.
 func (dal *dal) Save(ctx context.Context, po any) (int64, error) {
-	db := dal.DBWithCtx(ctx)
-	if db.Error != nil {
-		return 0, db.Error
+	op := makeOperation(OpSave, po, nil, nil, nil)
+	if err := dal.invoke(ctx, op, dal.save); err != nil {
+		return 0, err
 	}
+	return op.RowsAffected, nil
+}
 
-	res := db.Save(po)
-	if err := res.Error; err != nil {
-		return 0, nil
+func (dal *dal) save(ctx context.Context, op *Operation) error {
+	db := dal.dbOf(ctx, op)
+	if db.Error != nil {
+		return db.Error
 	}
-	return res.RowsAffected, nil
+
+	res := db.Save(op.PO)
+	op.RowsAffected = res.RowsAffected
+	return res.Error
 }
 
 // Delete delete by Where struct
 func (dal *dal) Delete(ctx context.Context, po any, where any) (int64, error) {
-	db := dal.DBWithCtx(ctx)
+	return dal.DeleteWithOptions(ctx, po, where)
+}
+
+// DeleteWithOptions delete by Where struct, combined with the extra expressions of options.
+//
+// 💡 HINT: the records are marked deleted rather than removed when the PO supports soft delete, which is still
+// an OpDelete to the interceptors with SoftDelete set.
+func (dal *dal) DeleteWithOptions(ctx context.Context, po any, where any, options ...QueryOption) (int64, error) {
+	op := makeOperation(OpDelete, po, where, nil, options)
+	if op.Config.softDelete != nil {
+		op.SoftDelete, op.Update = true, op.Config.softDelete
+	}
+	if err := dal.invoke(ctx, op, dal.delete); err != nil {
+		return 0, err
+	}
+	return op.RowsAffected, nil
+}
+
+func (dal *dal) delete(ctx context.Context, op *Operation) error {
+	db := dal.dbOf(ctx, op)
 	if db.Error != nil {
-		return 0, db.Error
+		return db.Error
 	}
 
-	gormWhere, err := gsql.BuildSQLWhereExpr(where)
+	gormWhere, err := buildWriteWhereExpr(op.Where, op.Config)
 	if err != nil {
-		return 0, err
+		return err
 	}
+	op.whereExpr = gormWhere
 	if gormWhere == nil {
-		return 0, fmt.Errorf("[gdal] can not delete without args")
+		return fmt.Errorf("[gdal] can not delete without args")
+	}
+	if op.SoftDelete {
+		res := db.Model(op.PO).Where(gormWhere).Updates(op.Update) // ignore_security_alert
+		op.RowsAffected = res.RowsAffected
+		return res.Error
 	}
-	db = db.Where(gormWhere).Delete(po) // ignore_security_alert
-	return db.RowsAffected, db.Error
+	res := db.Where(gormWhere).Delete(op.PO) // ignore_security_alert
+	op.RowsAffected = res.RowsAffected
+	return res.Error
 }
 
 // Update updates by Where struct & Update struct. The Where struct mustn't be nil.
 func (dal *dal) Update(ctx context.Context, po any, where any, update any) (int64, error) {
-	db := dal.DBWithCtx(ctx)
+	return dal.UpdateWithOptions(ctx, po, where, update)
+}
+
+// UpdateWithOptions updates by Where struct & Update struct, combined with the extra expressions of options.
+//
+// 💡 HINT: update can also be a map from column to value.
+//
+// 💡 HINT: if fields of Update struct tagged by `sql_min` are set, only the records no less than the floors are
+// updated, and InsufficientValueError returns when some records matched but none qualified. The matched records
+// below the floors are skipped silently if any qualified.
+func (dal *dal) UpdateWithOptions(ctx context.Context, po any, where any, update any, options ...QueryOption) (int64, error) {
+	op := makeOperation(OpUpdate, po, where, update, options)
+	if err := dal.invoke(ctx, op, dal.update); err != nil {
+		return 0, err
+	}
+	return op.RowsAffected, nil
+}
+
+func (dal *dal) update(ctx context.Context, op *Operation) error {
+	db := dal.dbOf(ctx, op)
 	if db.Error != nil {
-		return 0, db.Error
+		return db.Error
 	}
 
-	gormWhere, err := gsql.BuildSQLWhereExpr(where)
+	gormWhere, err := buildWriteWhereExpr(op.Where, op.Config)
 	if err != nil {
-		return 0, err
+		return err
 	}
+	op.whereExpr = gormWhere
 	if gormWhere == nil {
-		return 0, fmt.Errorf("can not update without args")
-	}
-	attrs, err := gsql.BuildSQLUpdate(update)
-	if err != nil {
-		return 0, err
+		return fmt.Errorf("can not update without args")
+	}
+	guard, guarded := op.Config.guard, op.Config.guarded
+	attrs, ok := op.Update.(map[string]any)
+	if !ok {
+		attrs, err = gsql.BuildSQLUpdate(op.Update)
+		if err != nil {
+			return err
+		}
+		guard, guarded, err = gsql.BuildSQLUpdateGuard(op.Update)
+		if err != nil {
+			return err
+		}
 	}
 	if len(attrs) == 0 {
-		return 0, nil
+		return nil
+	}
+	guardedWhere := gormWhere
+	if guard != nil { // the floors of columns tagged by sql_min
+		guardedWhere = clause.And(gormWhere, guard)
 	}
 
-	res := db.Model(po).Where(gormWhere).Updates(attrs) // ignore_security_alert
-	if err := res.Error; err != nil {
-		return 0, err
+	res := db.Model(op.PO).Where(guardedWhere).Updates(attrs) // ignore_security_alert
+	op.RowsAffected = res.RowsAffected
+	if res.Error == nil && guard != nil && res.RowsAffected == 0 {
+		return dal.checkGuard(ctx, op, gormWhere, guardedWhere, guarded)
+	}
+	return res.Error
+}
+
+// checkGuard tell why no record is updated under the floors of guarded columns: InsufficientValueError if some
+// records matched where but none of them are up to the floors, otherwise none matched, or the qualified are
+// unchanged, e.g. decreased by 0 on MySQL which reports the changed rows only.
+func (dal *dal) checkGuard(ctx context.Context, op *Operation, where, guardedWhere clause.Expression, guarded []string) error {
+	var matched, qualified int64
+	db := dal.dbOf(ctx, op).Clauses(dbresolver.Write).Model(op.PO)
+	if err := db.Session(&gorm.Session{}).Where(where).Count(&matched).Error; err != nil {
+		return err
+	}
+	if matched == 0 {
+		return nil
+	}
+	if err := db.Session(&gorm.Session{}).Where(guardedWhere).Count(&qualified).Error; err != nil {
+		return err
 	}
-	return res.RowsAffected, nil
+	if qualified > 0 {
+		return nil
+	}
+	return gerror.InsufficientValueErr(structTypeOf(op.PO), guarded)
 }
 
 // Find finds the records by Where struct
@@ -126,76 +251,166 @@ func (dal *dal) Update(ctx context.Context, po any, where any, update any) (int6
 //
 // 🚀 example:
 func (dal *dal) Find(ctx context.Context, po any, where any, options ...QueryOption) (err error) {
-	var db *gorm.DB
-	db, err = dal.whereDB(ctx, where, options...)
+	op := makeOperation(OpFind, po, where, nil, options)
+	return dal.invoke(ctx, op, dal.find)
+}
+
+func (dal *dal) find(ctx context.Context, op *Operation) error {
+	db, err := dal.whereDB(ctx, op)
 	if err != nil {
 		return err
 	}
 
-	if err := db.Find(po).Error; err != nil {
-		return err
-	}
-	return nil
+	res := db.Find(op.PO)
+	op.RowsAffected = res.RowsAffected
+	return res.Error
 }
 
 // First find the first records by Where struct
 func (dal *dal) First(ctx context.Context, po, where any, options ...QueryOption) error {
-	db, err := dal.whereDB(ctx, where, options...)
-	if err != nil {
-		return err
-	}
+	op := makeOperation(OpFirst, po, where, nil, options)
+	return dal.invoke(ctx, op, dal.first)
+}
 
-	if err = db.First(po).Error; err != nil {
+func (dal *dal) first(ctx context.Context, op *Operation) error {
+	db, err := dal.whereDB(ctx, op)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	res := db.First(op.PO)
+	op.RowsAffected = res.RowsAffected
+	return res.Error
 }
 
 // Count get the count by Where struct
 func (dal *dal) Count(ctx context.Context, po any, where any, options ...QueryOption) (int32, error) {
-	db := dal.DBWithCtx(ctx, options...)
+	op := makeOperation(OpCount, po, where, nil, options)
+	if err := dal.invoke(ctx, op, dal.count); err != nil {
+		return 0, err
+	}
+	return int32(op.RowsAffected), nil
+}
+
+func (dal *dal) count(ctx context.Context, op *Operation) error {
+	db := dal.dbOf(ctx, op)
 	if db.Error != nil {
-		return 0, db.Error
+		return db.Error
 	}
 
-	gormWhere, err := gsql.BuildSQLWhereExpr(where)
+	gormWhere, err := buildWhereExpr(op.Where, op.Config)
 	if err != nil {
-		return 0, err
+		return err
 	}
+	op.whereExpr = gormWhere
 
 	var count int64
-	if err := db.Model(po).Where(gormWhere).Count(&count).Error; err != nil { // ignore_security_alert
-		return 0, err
+	if err := db.Model(op.PO).Where(gormWhere).Count(&count).Error; err != nil { // ignore_security_alert
+		return err
 	}
-	return int32(count), nil
+	op.RowsAffected = count
+	return nil
 }
 
 // Exist judge if record found by where struct
 func (dal *dal) Exist(ctx context.Context, po any, where any, options ...QueryOption) (bool, error) {
-	db, err := dal.whereDB(ctx, where, options...)
-	if err != nil {
+	op := makeOperation(OpExist, po, where, nil, options)
+	if err := dal.invoke(ctx, op, dal.exist); err != nil {
 		return false, err
 	}
+	return op.RowsAffected > 0, nil
+}
+
+func (dal *dal) exist(ctx context.Context, op *Operation) error {
+	db, err := dal.whereDB(ctx, op)
+	if err != nil {
+		return err
+	}
 
-	if err := db.First(po).Error; err != nil {
+	if err := db.First(op.PO).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return false, nil
+			op.RowsAffected = 0
+			return nil
 		}
-		return false, err
+		return err
+	}
+	op.RowsAffected = 1
+	return nil
+}
+
+// Aggregate scan the aggregates of records by Where struct into result, grouped by the group columns if any.
+//
+// 💡 HINT: the group columns and aggregate expressions are selected by the options of GDAL.Aggregate.
+func (dal *dal) Aggregate(ctx context.Context, po any, where any, result any, options ...QueryOption) error {
+	op := makeOperation(OpAggregate, po, where, nil, options)
+	op.Result = result
+	return dal.invoke(ctx, op, dal.aggregate)
+}
+
+func (dal *dal) aggregate(ctx context.Context, op *Operation) error {
+	db, err := dal.whereDB(ctx, op)
+	if err != nil {
+		return err
+	}
+
+	opt := op.Config
+	if len(opt.aggregates) == 0 {
+		return fmt.Errorf("no aggregate expression selected")
+	}
+	columns := make([]any, 0, len(opt.groups)+len(opt.aggregates))
+	groupBy := clause.GroupBy{}
+	for _, group := range opt.groups {
+		columns = append(columns, clause.Column{Name: group})
+		groupBy.Columns = append(groupBy.Columns, clause.Column{Name: group})
+	}
+	for _, aggregate := range opt.aggregates {
+		columns = append(columns, aggregate)
+	}
+	db = db.Model(op.PO).Select(strings.TrimSuffix(strings.Repeat("?,", len(columns)), ","), columns...)
+	if len(groupBy.Columns) > 0 {
+		db = db.Clauses(groupBy)
 	}
-	return true, nil
+
+	res := db.Scan(op.Result)
+	op.RowsAffected = res.RowsAffected
+	return res.Error
+}
+
+// Pluck scan the values of column of records by Where struct into values, a pointer to slice.
+//
+// 💡 HINT: the values are distinct if selected by the options of gdal.Distinct.
+func (dal *dal) Pluck(ctx context.Context, po any, where any, column string, values any, options ...QueryOption) error {
+	op := makeOperation(OpPluck, po, where, nil, append(options[:len(options):len(options)], WithSelects([]string{column})))
+	op.Result = values
+	return dal.invoke(ctx, op, dal.pluck)
+}
+
+func (dal *dal) pluck(ctx context.Context, op *Operation) error {
+	db, err := dal.whereDB(ctx, op)
+	if err != nil {
+		return err
+	}
+	if len(op.Config.Selects) != 1 {
+		return fmt.Errorf("pluck needs exactly one column, but got %v", op.Config.Selects)
+	}
+	if op.Config.distinct {
+		db = db.Distinct()
+	}
+
+	res := db.Model(op.PO).Pluck(op.Config.Selects[0], op.Result)
+	op.RowsAffected = res.RowsAffected
+	return res.Error
 }
 
 // DBWithCtx embedded DB with context
 //
-// 💡 HINT:
+// 💡 HINT: if ctx carries a transaction by Transaction, DB joins it.
 //
 // ⚠️  WARNING:
 //
 // 🚀 example:
 func (dal *dal) DBWithCtx(ctx context.Context, options ...QueryOption) *gorm.DB {
-	return dal.DB(options...).WithContext(ctx)
+	return dal.dbWithConfig(ctx, MakeQueryConfig(options))
 }
 
 // DB embedded DB
@@ -206,31 +421,53 @@ func (dal *dal) DBWithCtx(ctx context.Context, options ...QueryOption) *gorm.DB
 //
 // 🚀 example:
 func (dal *dal) DB(options ...QueryOption) *gorm.DB {
-	opt := MakeQueryConfig(options)
+	return dal.dbByConfig(MakeQueryConfig(options))
+}
+
+func (dal *dal) dbByConfig(opt *QueryConfig) *gorm.DB {
 	if dal.db == nil {
 		return &gorm.DB{Error: gorm.ErrInvalidTransaction}
 	}
 	db := dal.db
-	db = db.Debug()
-
+	if opt.debug {
+		db = db.Debug()
+	}
 	if opt.readMaster {
 		db = db.Clauses(dbresolver.Write)
 	}
 	return db
 }
 
-func (dal *dal) whereDB(ctx context.Context, where any, options ...QueryOption) (db *gorm.DB, err error) {
-	db = dal.DBWithCtx(ctx, options...)
+func (dal *dal) dbWithConfig(ctx context.Context, opt *QueryConfig) *gorm.DB {
+	return joinTxIfHas(ctx, dal.dbByConfig(opt).WithContext(ctx))
+}
+
+// dbOf db to execute op, redacting the sensitive values of op from the SQL logged, and tracing the SQL error
+// logged by gorm.
+func (dal *dal) dbOf(ctx context.Context, op *Operation) *gorm.DB {
+	db := dal.dbByConfig(op.Config)
+	if db.Error == nil && len(op.sensitive) > 0 {
+		db = db.Session(&gorm.Session{Logger: &redactLogger{Interface: db.Logger, op: op}})
+	}
+	if db.Error == nil {
+		db = db.Session(&gorm.Session{Logger: &traceLogger{Interface: db.Logger, op: op}})
+	}
+	return joinTxIfHas(ctx, db.WithContext(ctx))
+}
+
+func (dal *dal) whereDB(ctx context.Context, op *Operation) (db *gorm.DB, err error) {
+	db = dal.dbOf(ctx, op)
 	if db.Error != nil {
 		return nil, db.Error
 	}
 
-	gormWhere, err := gsql.BuildSQLWhereExpr(where)
+	opt := op.Config
+	gormWhere, err := buildWhereExpr(op.Where, opt)
 	if err != nil {
 		return nil, err
 	}
+	op.whereExpr = gormWhere
 
-	opt := MakeQueryConfig(options)
 	if len(opt.Selects) > 0 {
 		db = db.Select(opt.Selects)
 	}
@@ -247,6 +484,118 @@ func (dal *dal) whereDB(ctx context.Context, where any, options ...QueryOption)
 	if opt.Offset != nil && opt.Limit == nil {
 		return nil, fmt.Errorf("can not set offset while limit was set")
 	}
+	if opt.locking != nil {
+		if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); !inTx {
+			return nil, gerror.LockingWithoutTxErr(opt.locking.Strength)
+		}
+		db = db.Clauses(*opt.locking)
+	}
 
 	return db, nil
 }
+
+// invoke execute op by invoker through the global interceptors, the interceptors of dal, and then logInterceptor.
+//
+// 💡 HINT: the sensitive values of op are redacted from the error before it passes back through interceptors.
+// The row locking is checked after interceptors, which may modify the options.
+func (dal *dal) invoke(ctx context.Context, op *Operation, invoker Invoker) error {
+	globalInterceptorsMu.RLock()
+	interceptors := make([]Interceptor, 0, len(globalInterceptors)+len(dal.interceptors)+1)
+	interceptors = append(interceptors, globalInterceptors...)
+	globalInterceptorsMu.RUnlock()
+	interceptors = append(interceptors, dal.interceptors...)
+	interceptors = append(interceptors, logInterceptor)
+	return chainInterceptors(interceptors, redactInvoker(lockingCheckedInvoker(invoker)))(ctx, op)
+}
+
+// lockingCheckedInvoker reject the row locking of op before invoker, unless op selects the records themselves,
+// i.e. find, first, exist and pluck without distinct. The others either ignore it, such as count, update and
+// delete, or are rejected by Postgres, such as the aggregates and DISTINCT.
+func lockingCheckedInvoker(invoker Invoker) Invoker {
+	return func(ctx context.Context, op *Operation) error {
+		if op.Config == nil || op.Config.locking == nil {
+			return invoker(ctx, op)
+		}
+		switch {
+		case op.Kind == OpFind, op.Kind == OpFirst, op.Kind == OpExist:
+		case op.Kind == OpPluck && !op.Config.distinct:
+		case op.Kind == OpPluck:
+			return gerror.LockingNotSupportedErr(op.Config.locking.Strength, "distinct")
+		default:
+			return gerror.LockingNotSupportedErr(op.Config.locking.Strength, string(op.Kind))
+		}
+		return invoker(ctx, op)
+	}
+}
+
+// withDB copy dal with db replaced, interceptors reserved.
+func (dal *dal) withDB(tx *gorm.DB) DAL {
+	cli := *dal
+	cli.db = tx
+	return &cli
+}
+
+// makeOperation make the descriptor of operation
+func makeOperation(kind OpKind, po any, where any, update any, options []QueryOption) *Operation {
+	return &Operation{
+		Kind:   kind,
+		Table:  tableOf(po),
+		PO:     po,
+		Where:  where,
+		Update: update,
+		Config: MakeQueryConfig(options),
+	}
+}
+
+// buildWhereExpr build the where struct into expression, then combine it with the extra expressions of opt by AND.
+//
+// 💡 HINT: where can also be clause.Expression.
+func buildWhereExpr(where any, opt *QueryConfig) (clause.Expression, error) {
+	gormWhere, err := buildStructWhereExpr(where)
+	if err != nil {
+		return nil, err
+	}
+	return mergeWhereExpr(gormWhere, opt), nil
+}
+
+// buildWriteWhereExpr build the where of Update and Delete like buildWhereExpr, but nil if the where struct is
+// empty, so that the extra expressions of opt, such as the soft-delete scope, never make an empty where match
+// every record.
+func buildWriteWhereExpr(where any, opt *QueryConfig) (clause.Expression, error) {
+	gormWhere, err := buildStructWhereExpr(where)
+	if err != nil || isEmptyExpr(gormWhere) {
+		return nil, err
+	}
+	return mergeWhereExpr(gormWhere, opt), nil
+}
+
+// buildStructWhereExpr build the where struct into expression.
+func buildStructWhereExpr(where any) (clause.Expression, error) {
+	if gormWhere, isExpr := where.(clause.Expression); isExpr {
+		return gormWhere, nil
+	}
+	return gsql.BuildSQLWhereExpr(where)
+}
+
+// mergeWhereExpr combine gormWhere with the extra expressions of opt by AND.
+func mergeWhereExpr(gormWhere clause.Expression, opt *QueryConfig) clause.Expression {
+	if len(opt.exprs) == 0 {
+		return gormWhere
+	}
+
+	var exprs []clause.Expression
+	if !isEmptyExpr(gormWhere) {
+		exprs = append(exprs, gormWhere)
+	}
+	exprs = append(exprs, opt.exprs...)
+	return clause.And(exprs...)
+}
+
+// isEmptyExpr whether expr builds nothing.
+func isEmptyExpr(expr clause.Expression) bool {
+	if expr == nil {
+		return true
+	}
+	and, ok := expr.(clause.AndConditions)
+	return ok && len(and.Exprs) == 0
+}
@@ -13,7 +13,7 @@ type User struct {
 	Hobbies    string    `gorm:"column:hobbies"`
 	CreateTime time.Time `gorm:"column:create_time"`
 	UpdateTime time.Time `gorm:"column:update_time"`
-	Deleted    bool      `gorm:"column:deleted"`
+	Deleted    bool      `gorm:"column:deleted" gdal:"soft_delete"`
 }
 
 // TableName the corresponding table name of User model struct
@@ -54,7 +54,7 @@ type UserUpdate struct {
 	Name         *string    `sql_field:"name"`
 	Balance      *int64     `sql_field:"balance"`
 	BalanceAdd   *int64     `sql_field:"balance" sql_expr:"+"`
-	BalanceMinus *int64     `sql_field:"balance" sql_expr:"-"`
+	BalanceMinus *int64     `sql_field:"balance" sql_expr:"-" sql_min:"0"`
 	UpdateTime   *time.Time `sql_field:"update_time"`
 	Deleted      *bool      `sql_field:"deleted"`
 }
@@ -72,6 +72,25 @@ func main() {
 		fmt.Println(err)
 	}
 
+	{ // upsert by Update struct
+		now := time.Now()
+		po := model.User{
+			ID:         110,
+			Name:       "dirac",
+			Balance:    100,
+			CreateTime: now,
+			UpdateTime: now,
+			Deleted:    false,
+		}
+		update := &model.UserUpdate{
+			BalanceAdd: gptr.Of[int64](100),
+			UpdateTime: gptr.Of(now),
+		}
+		// INSERT INTO `user` (`name`,`balance`,`hobbies`,`create_time`,`update_time`,`deleted`,`id`) VALUES ('dirac',100,'','2023-07-14 21:29:08.305','2023-07-14 21:29:08.305',false,110) ON DUPLICATE KEY UPDATE `balance`=balance + 100,`update_time`='2023-07-14 21:29:08.305'
+		err := userDAL.Upsert(ctx, &po, nil, update)
+		fmt.Println(err)
+	}
+
 	{ // multiple create
 		now := time.Now()
 		hobbies1, _ := json.Marshal([]string{"book", "coding"})
@@ -110,7 +129,7 @@ func main() {
 		update := &model.UserUpdate{
 			BalanceAdd: gptr.Of[int64](10),
 		}
-		// UPDATE `user` SET `balance`=balance + 10 WHERE (`id` IN (110,120) AND JSON_CONTAINS(hobbies, 'book') AND `deleted` = false)
+		// UPDATE `user` SET `balance`=`user`.`balance` + 10 WHERE (`id` IN (110,120) AND JSON_CONTAINS(`hobbies`, 'book') AND `deleted` = false)
 		numUpdate, err := userDAL.MUpdate(ctx, where, update)
 		fmt.Println(numUpdate)
 		fmt.Println(err)
@@ -120,7 +139,7 @@ func main() {
 		update := &model.UserUpdate{
 			BalanceMinus: gptr.Of[int64](20),
 		}
-		// UPDATE `user` SET `balance`=balance - 20 WHERE `id` = 130
+		// UPDATE `user` SET `balance`=balance - 20 WHERE (`id` = 130 AND `balance` >= 20)
 		err := userDAL.UpdateByID(ctx, 130, update)
 		fmt.Println(err)
 	}
@@ -177,13 +196,13 @@ func main() {
 			update := &model.UserUpdate{
 				BalanceMinus: gptr.Of[int64](20),
 			}
-			// UPDATE `user` SET `balance`=balance - 20 WHERE `id` = 130
+			// UPDATE `user` SET `balance`=balance - 20 WHERE (`id` = 130 AND `balance` >= 20)
 			err := userDAL.WithTx(tx).UpdateByID(ctx, 130, update)
 			if err != nil {
 				return err // rollback
 			}
 
-			// DELETE FROM `user` WHERE `id` = 130
+			// UPDATE `user` SET `deleted`=true WHERE `id` = 130 AND `deleted` = false
 			_, err = userDAL.WithTx(tx).DeleteByID(ctx, 130)
 			if err != nil {
 				return err // rollback
@@ -194,22 +213,62 @@ func main() {
 		fmt.Println(finalErr)
 	}
 
-	{ // physically delete
+	{ // transaction carried by context
+		finalErr := gdal.Transaction(ctx, DB, func(ctx context.Context) error {
+
+			update := &model.UserUpdate{
+				BalanceAdd: gptr.Of[int64](20),
+			}
+			// UPDATE `user` SET `balance`=balance + 20 WHERE `id` = 130
+			err := userDAL.UpdateByID(ctx, 130, update)
+			if err != nil {
+				return err // rollback
+			}
+
+			gdal.AfterCommit(ctx, func(ctx context.Context) {
+				fmt.Println("committed")
+			})
+			return nil // commit
+		})
+		fmt.Println(finalErr)
+	}
+
+	{ // logically delete, because field `Deleted` of model.User is tagged by `gdal:"soft_delete"`
 		where := &model.UserWhere{
 			IDIn: []int64{110, 120},
 		}
-		// DELETE FROM `user` WHERE `id` IN (110,120)
+		// UPDATE `user` SET `deleted`=true WHERE (`id` IN (110,120) AND `deleted` = false)
 		numDeleted, err := userDAL.Delete(ctx, where)
 		fmt.Println(numDeleted)
 		fmt.Println(err)
 	}
 
-	{ // physically delete by id
-		// DELETE FROM `user` WHERE `id` = 130
+	{ // restore the logically deleted
+		where := &model.UserWhere{
+			IDIn: []int64{110, 120},
+		}
+		// UPDATE `user` SET `deleted`=false WHERE (`id` IN (110,120) AND `deleted` = true)
+		numRestored, err := userDAL.Restore(ctx, where)
+		fmt.Println(numRestored)
+		fmt.Println(err)
+	}
+
+	{ // logically delete by id
+		// UPDATE `user` SET `deleted`=true WHERE `id` = 130 AND `deleted` = false
 		numDeleted, err := userDAL.DeleteByID(ctx, 130)
 		fmt.Println(numDeleted)
 		fmt.Println(err)
 	}
+
+	{ // physically delete
+		where := &model.UserWhere{
+			IDIn: []int64{110, 120},
+		}
+		// DELETE FROM `user` WHERE `id` IN (110,120)
+		numDeleted, err := userDAL.HardDelete(ctx, where)
+		fmt.Println(numDeleted)
+		fmt.Println(err)
+	}
 }
 
 func RunMigrations() {
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/dirac-lee/gdal/gutil/greflect"
+	"github.com/dirac-lee/gdal/gutil/gsql"
 	"gorm.io/hints"
 )
 
@@ -16,7 +17,7 @@ type ForceIndexer interface {
 
 // forceIndexIfHas  if Where implements ForceIndexer，force the index by ForceIndexer, otherwise, it depends on db
 //
-// 💡 HINT:
+// 💡 HINT: hint `USE INDEX` is MySQL only, so that it is omitted for other dialects.
 //
 // ⚠️  WARNING: please implement ForceIndexer for Where in spite of *Where
 //
@@ -30,5 +31,8 @@ func (gdal *GDAL[PO, Where, Update]) forceIndexIfHas(ctx context.Context, where
 	if len(forceIndex) == 0 { // Where 没有指定强制索引，由数据库自行决定
 		return txDAL
 	}
+	if db := gdal.DB(); db == nil || db.Dialector == nil || db.Dialector.Name() != gsql.DialectMySQL {
+		return txDAL
+	}
 	return gdal.Clauses(hints.UseIndex(forceIndex))
 }
@@ -2,10 +2,11 @@ package gdal
 
 import (
 	"context"
+	"sort"
 
 	"github.com/dirac-lee/gdal/gutil/gerror"
-	"github.com/dirac-lee/gdal/gutil/gptr"
 	"github.com/dirac-lee/gdal/gutil/gslice"
+	"github.com/dirac-lee/gdal/gutil/gsql"
 	"github.com/dirac-lee/gdal/gutil/gvalue"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -19,7 +20,10 @@ import (
 // 💡 HINT: *Where can implement interface InjectDefaulter so that we can inject the customized
 // default value into struct `where` when you query or update.
 //
-// 💡 HINT: Where can implement interface ForceIndexer, if so, we will force index when you query.
+// 💡 HINT: Where can implement interface ForceIndexer, if so, we will force index when you query on MySQL.
+//
+// 💡 HINT: PO can tag a bool or integer field by `gdal:"soft_delete"`, if so, records are deleted logically,
+// and soft-deleted records are filtered out unless WithUnscoped.
 //
 // ⚠️  WARNING: PO must implement interface Tabler and set the corresponding table name.
 //
@@ -38,7 +42,7 @@ import (
 //		Active     bool       `gorm:"column:active"`
 //		CreateTime time.Time  `gorm:"column:create_time"`
 //		UpdateTime time.Time  `gorm:"column:update_time"`
-//		IsDeleted  bool       `gorm:"column:is_deleted"`
+//		IsDeleted  bool       `gorm:"column:is_deleted" gdal:"soft_delete"`
 //	}
 //
 //	func (u User) TableName() string {
@@ -162,8 +166,68 @@ func (gdal *GDAL[PO, Where, Update]) Create(ctx context.Context, po *PO) error {
 // ("find",18,"2023-06-11 09:38:14",NULL,NULL,false,"2023-06-11 09:38:14.484","2023-06-11 09:38:14.484",false)
 // RETURNING `id`
 func (gdal *GDAL[PO, Where, Update]) MCreate(ctx context.Context, pos *[]*PO) (int64, error) {
-	tx := gdal.DAL.DBWithCtx(ctx).Table(gdal.TableName()).CreateInBatches(pos, 100)
-	return tx.RowsAffected, tx.Error
+	return gdal.extended().MCreate(ctx, pos, 100)
+}
+
+// Upsert insert a single record, or apply update to the existing record on conflict.
+//
+// 💡 HINT: only the non-nil fields of update are applied on conflict, including `sql_expr` updaters such as `+`.
+// If no field of update is set, the conflict is ignored. Primary key `id` is the conflict target when
+// conflictColumns is empty.
+//
+// 💡 HINT: the SQL is generated by dialect: `ON DUPLICATE KEY UPDATE` for MySQL, `ON CONFLICT ... DO UPDATE`
+// for Postgres and SQLite, and `MERGE` for SQL Server.
+//
+// ⚠️  WARNING: conflictColumns must be covered by a unique index, and MySQL & SQL Server ignore them
+// in favor of their own unique keys & primary keys.
+//
+// ⚠️  WARNING: neither fields tagged by `sql_min` nor the expected version is supported, since they can not be
+// checked on conflict, use UpdateByID instead. The version is increased by 1 on conflict if PO has version column.
+//
+// 🚀 example:
+//
+//	user := tests.User{
+//		ID:   110,
+//		Name: "Ella",
+//		Age:  17,
+//	}
+//	update := &tests.UserUpdate{
+//		Name: gptr.Of("Ella"),
+//	}
+//	err := UserDAL.Upsert(ctx, &user, gslice.Of("id"), update)
+//
+// SQL:
+// INSERT INTO `user` (`name`,`age`,...,`id`) VALUES ("Ella",17,...,110) ON CONFLICT (`id`) DO UPDATE SET `name`="Ella" RETURNING `id`
+func (gdal *GDAL[PO, Where, Update]) Upsert(ctx context.Context, po *PO, conflictColumns []string, update *Update) error {
+	onConflict, err := gdal.buildOnConflict(conflictColumns, update)
+	if err != nil {
+		return err
+	}
+	return gdal.Clauses(onConflict).Create(ctx, po)
+}
+
+// MUpsert insert multiple records, or apply update to the existing records on conflict, and return success count.
+//
+// 💡 HINT: ref Upsert.
+//
+// ⚠️  WARNING: MySQL counts an updated record as 2 affected rows.
+//
+// 🚀 example:
+//
+//	users := []*tests.User{
+//		GetUser("upsert"),
+//		GetUser("upsert"),
+//	}
+//	update := &tests.UserUpdate{
+//		UpdateTime: gptr.Of(time.Now()),
+//	}
+//	total, err := UserDAL.MUpsert(ctx, &users, gslice.Of("id"), update)
+func (gdal *GDAL[PO, Where, Update]) MUpsert(ctx context.Context, pos *[]*PO, conflictColumns []string, update *Update) (int64, error) {
+	onConflict, err := gdal.buildOnConflict(conflictColumns, update)
+	if err != nil {
+		return 0, err
+	}
+	return gdal.Clauses(onConflict).MCreate(ctx, pos)
 }
 
 // Count
@@ -184,6 +248,10 @@ func (gdal *GDAL[PO, Where, Update]) MCreate(ctx context.Context, pos *[]*PO) (i
 // SQL:
 // SELECT count(*) FROM `user` WHERE `active` = true and `is_deleted` = false and `birthday` >= "1999-01-01 00:00:00" and `birthday` < "2019-01-01 00:00:00"
 func (gdal *GDAL[PO, Where, Update]) Count(ctx context.Context, where *Where, options ...QueryOption) (int64, error) {
+	options, err := gdal.scopeIfSoftDelete(options) // filter out soft-deleted records unless unscoped.
+	if err != nil {
+		return 0, err
+	}
 	injectDefaultIfHas(where)                      // when field is not set in `where`,  insert customized default value  if customer has set it.
 	indexedDAL := gdal.forceIndexIfHas(ctx, where) // force index if  it is set in `where`.
 	count, err := indexedDAL.DAL.Count(ctx, gdal.MakePO(), where, options...)
@@ -217,6 +285,10 @@ func (gdal *GDAL[PO, Where, Update]) Find(ctx context.Context, pos any, where an
 	if err != nil {
 		return err
 	}
+	options, err = gdal.scopeIfSoftDelete(options) // filter out soft-deleted records unless unscoped.
+	if err != nil {
+		return err
+	}
 	injectDefaultIfHas(where)                      // when field is not set in `where`,  insert customized default value  if customer has set it.
 	indexedDAL := gdal.forceIndexIfHas(ctx, where) // force index if  it is set in `where`.
 
@@ -255,6 +327,10 @@ func (gdal *GDAL[PO, Where, Update]) First(ctx context.Context, po any, where an
 	if err != nil {
 		return err
 	}
+	options, err = gdal.scopeIfSoftDelete(options) // filter out soft-deleted records unless unscoped.
+	if err != nil {
+		return err
+	}
 	injectDefaultIfHas(where)                                      // when field is not set in `where`,  insert customized default value  if customer has set it.
 	indexedDAL := gdal.forceIndexIfHas(ctx, where)                 // force index if  it is set in `where`.
 	options = append(gslice.Of(WithSelects(selector)), options...) // as for selected columns, customer first.
@@ -288,7 +364,12 @@ func (gdal *GDAL[PO, Where, Update]) MQuery(ctx context.Context, where *Where, o
 //
 // 💡 HINT: When you just need complete persistent objects by primary key list, this method is what you want.
 //
-// ⚠️  WARNING: nothing returns when ids is empty slice.å
+// 💡 HINT: ids is a slice of primary keys, ref QueryByID. Duplicated ids are queried once, and the records are
+// returned in the order of ids. The ids are queried by chunks of 500 per statement, ref WithChunkSize and
+// WithParallel. Use WithStrict to report the ids not found.
+//
+// ⚠️  WARNING: nothing returns without any statement when ids is empty slice. With WithOrder, WithLimit or
+// WithOffset, the records are queried by a single statement in the order given instead.
 //
 // 🚀 example:
 //
@@ -297,13 +378,16 @@ func (gdal *GDAL[PO, Where, Update]) MQuery(ctx context.Context, where *Where, o
 // SQL:
 // SELECT `id`,`name`,`age`,`birthday`,`company_id`,`manager_id`,`active`,`create_time`,`update_time`,`is_deleted`
 // FROM `user` WHERE `id` in (123, 456, 789) ORDER BY birthday LIMIT 10
-func (gdal *GDAL[PO, Where, Update]) MQueryByIDs(ctx context.Context, ids []int64, options ...QueryOption) ([]*PO, error) {
-	where := &idWhere{
-		IDMustIn: &ids,
+func (gdal *GDAL[PO, Where, Update]) MQueryByIDs(ctx context.Context, ids any, options ...QueryOption) ([]*PO, error) {
+	meta, err := getPOMeta[PO]()
+	if err != nil {
+		return nil, err
 	}
-	var pos []*PO
-	err := gdal.Find(ctx, &pos, where, options...)
-	return pos, err
+	keys, err := keysOf(ids)
+	if err != nil {
+		return nil, err
+	}
+	return gdal.mQueryByIDs(ctx, meta, keys, options)
 }
 
 // MQueryByPagingOpt query by paging options.
@@ -326,7 +410,8 @@ func (gdal *GDAL[PO, Where, Update]) MQueryByIDs(ctx context.Context, ids []int6
 // SELECT count(*) FROM `user` WHERE `active` = true and `is_deleted` = false and `birthday` >= "1999-01-01 00:00:00" and `birthday` < "2019-01-01 00:00:00"
 // SELECT `id`,`name`,`age`,`birthday`,`company_id`,`manager_id`,`active`,`create_time`,`update_time`,`is_deleted` FROM `user` WHERE `active` = true and `is_deleted` = false and `birthday` >= "1999-01-01 00:00:00" and `birthday` < "2019-01-01 00:00:00" ORDER BY birthday LIMIT 10
 func (gdal *GDAL[PO, Where, Update]) MQueryByPagingOpt(ctx context.Context, where *Where, options ...QueryOption) ([]*PO, int64, error) {
-	count, err := gdal.Count(ctx, where)
+	countOptions := append(options[:len(options):len(options)], withoutLocking()) // only the records found are locked
+	count, err := gdal.Count(ctx, where, countOptions...)
 	if err != nil || count == 0 { // skip query when count = 0
 		return nil, 0, err
 	}
@@ -364,6 +449,173 @@ func (gdal *GDAL[PO, Where, Update]) MQueryByPaging(ctx context.Context, where *
 	return gdal.MQueryByPagingOpt(ctx, where, options...)
 }
 
+// MQueryByCursorOpt query by cursor (keyset) paging options.
+//
+// 💡 HINT: unlike MQueryByPagingOpt, there is no count query and no OFFSET, the next page continues from
+// the last record of the previous one by predicate such as `create_time < ? OR (create_time = ? AND id < ?)`,
+// so deep pages are as fast as the first one and records will not shift between pages when writes land.
+//
+// 💡 HINT: the second return is the cursor of the next page, and the third return is whether there are more
+// records after this page. Primary key `id` is appended to sorts as tie-breaker if it is absent.
+//
+// ⚠️  WARNING: WithLimit is required, WithOffset and WithOrder are not allowed. Sort columns must be mapped by PO
+// and must not be nullable, fields such as *time.Time or sql.NullString are rejected.
+//
+// ⚠️  WARNING: cursors are signed, call SetCursorSecret with the same secret on all instances before cursor paging.
+//
+// 🚀 example:
+//
+//	where := &tests.UserWhere{
+//		Active: gptr.Of(true),
+//	}
+//	sorts := gslice.Of(gdal.Desc("create_time"))
+//	users, next, hasMore, err := UserDAL.MQueryByCursorOpt(ctx, where, sorts, gdal.WithLimit(10))
+//	users, next, hasMore, err = UserDAL.MQueryByCursorOpt(ctx, where, sorts, gdal.WithLimit(10), gdal.WithCursor(next))
+//
+// SQL:
+// SELECT `id`,`name`,`age`,`birthday`,`company_id`,`manager_id`,`active`,`create_time`,`update_time`,`is_deleted` FROM `user` WHERE `active` = true and `is_deleted` = false ORDER BY create_time DESC, id DESC LIMIT 11
+// SELECT `id`,`name`,`age`,`birthday`,`company_id`,`manager_id`,`active`,`create_time`,`update_time`,`is_deleted` FROM `user` WHERE (`active` = true and `is_deleted` = false AND (`create_time` < "2023-06-11 09:38:14" OR (`create_time` = "2023-06-11 09:38:14" AND `id` < 110))) ORDER BY create_time DESC, id DESC LIMIT 11
+func (gdal *GDAL[PO, Where, Update]) MQueryByCursorOpt(ctx context.Context, where *Where, sorts []CursorSort, options ...QueryOption) ([]*PO, string, bool, error) {
+	opt := MakeQueryConfig(options)
+	if opt.Limit == nil || *opt.Limit <= 0 {
+		return nil, "", false, gerror.GDALErrorf("cursor paging needs positive limit")
+	}
+	if opt.Offset != nil || opt.Order != nil {
+		return nil, "", false, gerror.GDALErrorf("cursor paging can not set offset or order, use sorts instead")
+	}
+	if err := checkCursorSecret(); err != nil {
+		return nil, "", false, err
+	}
+	meta, err := getPOMeta[PO]()
+	if err != nil {
+		return nil, "", false, err
+	}
+	sorts, err = completeCursorSorts(meta, sorts)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	limit := *opt.Limit
+	signature := cursorSignature(gdal.TableName(), sorts)
+	options = append(options, WithOrder(cursorOrder(sorts)), WithLimit(limit+1)) // one more record to know whether there are more.
+	if opt.Cursor != nil && len(*opt.Cursor) > 0 {
+		values, err := decodeCursor(meta, signature, sorts, *opt.Cursor)
+		if err != nil {
+			return nil, "", false, err
+		}
+		options = append(options, withExprs(buildCursorExpr(sorts, values)))
+	}
+
+	pos, err := gdal.MQuery(ctx, where, options...)
+	if err != nil || len(pos) <= limit {
+		return pos, "", false, err
+	}
+	pos = pos[:limit]
+	next, err := encodeCursor(meta, signature, sorts, pos[limit-1])
+	if err != nil {
+		return nil, "", false, err
+	}
+	return pos, next, true, nil
+}
+
+// MQueryByCursor query by cursor (keyset) paging.
+//
+// 💡 HINT: ref MQueryByCursorOpt
+//
+// 🚀 example:
+//
+//	where := &tests.UserWhere{
+//		Active: gptr.Of(true),
+//	}
+//	users, next, hasMore, err := UserDAL.MQueryByCursor(ctx, where, "", 10, gdal.Desc("create_time"))
+//	users, next, hasMore, err = UserDAL.MQueryByCursor(ctx, where, next, 10, gdal.Desc("create_time"))
+//
+// SQL:
+// SELECT `id`,`name`,`age`,`birthday`,`company_id`,`manager_id`,`active`,`create_time`,`update_time`,`is_deleted` FROM `user` WHERE `active` = true and `is_deleted` = false ORDER BY create_time DESC, id DESC LIMIT 11
+// SELECT `id`,`name`,`age`,`birthday`,`company_id`,`manager_id`,`active`,`create_time`,`update_time`,`is_deleted` FROM `user` WHERE (`active` = true and `is_deleted` = false AND (`create_time` < "2023-06-11 09:38:14" OR (`create_time` = "2023-06-11 09:38:14" AND `id` < 110))) ORDER BY create_time DESC, id DESC LIMIT 11
+func (gdal *GDAL[PO, Where, Update]) MQueryByCursor(ctx context.Context, where *Where, cursor string, limit int, sorts ...CursorSort) ([]*PO, string, bool, error) {
+	return gdal.MQueryByCursorOpt(ctx, where, sorts, WithLimit(limit), WithCursor(cursor))
+}
+
+// Iterate walks through records by condition in batches of batchSize, and calls fn with each batch.
+//
+// 💡 HINT: records are walked in ascending order of primary key by predicate `id > ?` instead of OFFSET,
+// so that memory is bounded by batchSize and every batch is as fast as the first one. As for composite
+// primary key, predicate `a > ? OR (a = ? AND b > ?)` is used, since SQL Server has no row value comparison.
+//
+// 💡 HINT: use WithCheckpoint to resume from the primary key of the last record you have handled.
+//
+// ⚠️  WARNING: iteration stops when ctx is done or fn returns error, and the error is returned.
+// WithLimit, WithOffset and WithOrder are not allowed.
+//
+// 🚀 example:
+//
+//	where := &tests.UserWhere{
+//		Active: gptr.Of(true),
+//	}
+//	err := UserDAL.Iterate(ctx, where, 100, func(users []*tests.User) error {
+//		return handle(users)
+//	})
+//
+// SQL:
+// SELECT `id`,`name`,`age`,`birthday`,`company_id`,`manager_id`,`active`,`create_time`,`update_time`,`is_deleted` FROM `user` WHERE `active` = true and `is_deleted` = false ORDER BY id ASC LIMIT 100
+// SELECT `id`,`name`,`age`,`birthday`,`company_id`,`manager_id`,`active`,`create_time`,`update_time`,`is_deleted` FROM `user` WHERE (`active` = true and `is_deleted` = false AND `id` > 100) ORDER BY id ASC LIMIT 100
+func (gdal *GDAL[PO, Where, Update]) Iterate(ctx context.Context, where *Where, batchSize int, fn func([]*PO) error, options ...QueryOption) error {
+	if batchSize <= 0 {
+		return gerror.GDALErrorf("iterate needs positive batch size")
+	}
+	opt := MakeQueryConfig(options)
+	if opt.Limit != nil || opt.Offset != nil || opt.Order != nil {
+		return gerror.GDALErrorf("iterate can not set limit, offset or order")
+	}
+	meta, err := getPOMeta[PO]()
+	if err != nil {
+		return err
+	}
+	if len(meta.primaryKeys) == 0 {
+		return gerror.GDALErrorf("can not iterate model (%v) without primary key", meta.structType)
+	}
+
+	sorts := make([]CursorSort, 0, len(meta.primaryKeys))
+	for _, column := range meta.primaryKeys {
+		sorts = append(sorts, Asc(column))
+	}
+	var checkpoint []any // values of primary key columns of the last record
+	if opt.Checkpoint != nil {
+		checkpoint, err = meta.keyValues(opt.Checkpoint)
+		if err != nil {
+			return err
+		}
+	}
+	options = append(options, WithOrder(cursorOrder(sorts)), WithLimit(batchSize))
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batchOptions := options
+		if checkpoint != nil {
+			batchOptions = append(batchOptions[:len(batchOptions):len(batchOptions)], withExprs(buildCursorExpr(sorts, checkpoint)))
+		}
+		pos, err := gdal.MQuery(ctx, where, batchOptions...)
+		if err != nil {
+			return err
+		}
+		if len(pos) == 0 {
+			return nil
+		}
+		if err := fn(pos); err != nil {
+			return err
+		}
+		if len(pos) < batchSize {
+			return nil
+		}
+		checkpoint = make([]any, 0, len(meta.primaryKeys))
+		for _, column := range meta.primaryKeys {
+			checkpoint = append(checkpoint, meta.valueOf(pos[len(pos)-1], column))
+		}
+	}
+}
+
 // QueryFirst query the first record by condition.
 //
 // 💡 HINT: ref First.
@@ -396,6 +648,9 @@ func (gdal *GDAL[PO, Where, Update]) QueryFirst(ctx context.Context, where *Wher
 
 // QueryByID query the record by primary key
 //
+// 💡 HINT: primary key is read from gorm tag `primaryKey`, or column `id` by default. id can be the value of
+// single primary key of any type, or the key struct of composite primary key, ref KeyOf.
+//
 // ⚠️  WARNING: if primary key is not exist, return nil pointer.
 //
 // 🚀 example:
@@ -404,12 +659,13 @@ func (gdal *GDAL[PO, Where, Update]) QueryFirst(ctx context.Context, where *Wher
 //
 // SQL:
 // SELECT `id`,`name`,`age`,`birthday`,`company_id`,`manager_id`,`active`,`create_time`,`update_time`,`is_deleted` FROM `user` WHERE `id` = 123 ORDER BY `user`.`id` LIMIT 1
-func (gdal *GDAL[PO, Where, Update]) QueryByID(ctx context.Context, id int64, options ...QueryOption) (*PO, error) {
-	where := &idWhere{
-		ID: gptr.Of(id),
+func (gdal *GDAL[PO, Where, Update]) QueryByID(ctx context.Context, id any, options ...QueryOption) (*PO, error) {
+	where, err := gdal.pkWhere(id)
+	if err != nil {
+		return nil, err
 	}
 	var po PO
-	err := gdal.First(ctx, &po, where, options...)
+	err = gdal.First(ctx, &po, where, options...)
 	if err != nil {
 		if gerror.IsErrRecordNotFound(err) {
 			return nil, nil
@@ -419,52 +675,119 @@ func (gdal *GDAL[PO, Where, Update]) QueryByID(ctx context.Context, id int64, op
 	return &po, err
 }
 
+// Exist judge if any record satisfies condition
+//
+// 💡 HINT: ref First.
+//
+// 🚀 example:
+//
+//	where := &UserWhere {
+//		Name: gptr.Of("dirac"),
+//	}
+//	exist, err := userDAL.Exist(ctx, where)
+//
+// SQL:
+// SELECT `id`,`name`,`age`,`birthday`,`company_id`,`manager_id`,`active`,`create_time`,`update_time`,`is_deleted`
+// FROM `user` WHERE `name` = 'dirac' and `is_deleted` = false LIMIT 1
+func (gdal *GDAL[PO, Where, Update]) Exist(ctx context.Context, where *Where, options ...QueryOption) (bool, error) {
+	var po PO
+	selector, err := GetSelectorFromPOs(&po) // 根据 PO gorm tag 确定 select 字段列表
+	if err != nil {
+		return false, err
+	}
+	options, err = gdal.scopeIfSoftDelete(options) // filter out soft-deleted records unless unscoped.
+	if err != nil {
+		return false, err
+	}
+	injectDefaultIfHas(where)                                      // when field is not set in `where`,  insert customized default value  if customer has set it.
+	indexedDAL := gdal.forceIndexIfHas(ctx, where)                 // force index if  it is set in `where`.
+	options = append(gslice.Of(WithSelects(selector)), options...) // as for selected columns, customer first.
+	return indexedDAL.DAL.Exist(ctx, &po, where, options...)
+}
+
 // MUpdate updates multiple records by condition, return success count
 //
-// 💡 HINT:
+// 💡 HINT: soft-deleted records are not updated unless WithUnscoped.
 //
-// ⚠️  WARNING:
+// 💡 HINT: if PO has version column tagged by `gdal:"version"`, the version is increased by 1. Moreover,
+// if the version field of update is set, only the records of that version are updated, and
+// VersionConflictError returns when none matched.
+//
+// ⚠️  WARNING: the version check is optional for updating by condition. If the version field of update is not
+// set, the records are updated whatever their versions are. UpdateByID requires it.
+//
+// 💡 HINT: if fields of update tagged by `sql_min` are set, such as `sql_expr:"-" sql_min:"0"`, only the records
+// no less than the floors are updated, and InsufficientValueError returns when some records matched but none
+// qualified. No error returns when none matched.
+//
+// ⚠️  WARNING: the matched records below the floors are skipped silently as long as any qualified, so compare
+// the returned count with the expected when all of them matter.
+//
+// ⚠️  WARNING: where must not be empty, even if the soft-delete scope or InjectDefaulter adds conditions.
 //
 // 🚀 example:
-func (gdal *GDAL[PO, Where, Update]) MUpdate(ctx context.Context, where *Where, update *Update) (int64, error) {
+func (gdal *GDAL[PO, Where, Update]) MUpdate(ctx context.Context, where *Where, update *Update, options ...QueryOption) (int64, error) {
+	if err := checkWhereNotEmpty(where, "update"); err != nil { // before injecting default, which is not a condition of caller.
+		return 0, err
+	}
+	options, err := gdal.scopeIfSoftDelete(options) // filter out soft-deleted records unless unscoped.
+	if err != nil {
+		return 0, err
+	}
 	injectDefaultIfHas(where) // when field is not set in `where`,  insert customized default value  if customer has set it.
-	return gdal.DAL.Update(ctx, gdal.MakePO(), where, update)
+	return gdal.update(ctx, where, update, false, options...)
 }
 
 // Update updates records by condition
 //
-// 💡 HINT:
+// 💡 HINT: ref MUpdate.
 //
-// ⚠️  WARNING:
+// ⚠️  WARNING: the version is checked only if the version field of update is set, ref MUpdate.
 //
 // 🚀 example:
-func (gdal *GDAL[PO, Where, Update]) Update(ctx context.Context, where *Where, update *Update) error {
-	injectDefaultIfHas(where) // when field is not set in `where`,  insert customized default value  if customer has set it.
-	_, err := gdal.MUpdate(ctx, where, update)
+func (gdal *GDAL[PO, Where, Update]) Update(ctx context.Context, where *Where, update *Update, options ...QueryOption) error {
+	_, err := gdal.MUpdate(ctx, where, update, options...)
 	return err
 }
 
 // UpdateByID updates single record by primary key
 //
-// 💡 HINT:
+// 💡 HINT: ref MUpdate and QueryByID.
 //
-// ⚠️  WARNING:
+// ⚠️  WARNING: if PO has version column, the version field of update must be set as the expected version,
+// otherwise, it fails without updating. Use UpdateWithRetry to load, mutate and save the record.
 //
 // 🚀 example:
-func (gdal *GDAL[PO, Where, Update]) UpdateByID(ctx context.Context, id int64, update *Update) error {
-	_, err := gdal.DAL.Update(ctx, gdal.MakePO(), idWhere{ID: &id}, update)
+func (gdal *GDAL[PO, Where, Update]) UpdateByID(ctx context.Context, id any, update *Update, options ...QueryOption) error {
+	where, err := gdal.pkWhere(id)
+	if err != nil {
+		return err
+	}
+	options, err = gdal.scopeIfSoftDelete(options) // filter out soft-deleted records unless unscoped.
+	if err != nil {
+		return err
+	}
+	_, err = gdal.update(ctx, where, update, true, options...)
 	return err
 }
 
 // Save saves single record
 //
-// 💡 HINT:
+// 💡 HINT: if PO has version column and po has been created, po is saved only when its version matches
+// the record's, and then its version is increased; otherwise VersionConflictError returns.
 //
 // ⚠️  WARNING:
 //
 // 🚀 example:
 func (gdal *GDAL[PO, Where, Update]) Save(ctx context.Context, po *PO) error {
-	_, err := gdal.DAL.Save(ctx, po)
+	meta, err := getPOMeta[PO]()
+	if err != nil {
+		return err
+	}
+	if len(meta.version) > 0 && !meta.hasZeroKey(po) {
+		return gdal.saveWithVersion(ctx, meta, po, meta.valueOf(po, meta.version))
+	}
+	_, err = gdal.DAL.Save(ctx, po)
 	return err
 }
 
@@ -479,26 +802,94 @@ func (gdal *GDAL[PO, Where, Update]) MSave(ctx context.Context, pos *[]*PO) (int
 	return gdal.DAL.Save(ctx, pos)
 }
 
-// Delete deletes physically by condition
+// Delete deletes by condition, and return success count
 //
-// 💡 HINT:
+// 💡 HINT: if PO has a field tagged by `gdal:"soft_delete"`, records are deleted logically by setting
+// the column to `true` or `1`; otherwise, they are deleted physically. The version is increased by 1 as well when
+// deleted logically if PO has version column.
+//
+// ⚠️  WARNING: where must not be empty, even if the soft-delete scope adds a condition.
+//
+// 🚀 example:
+//
+//	where := &UserWhere {
+//		Name: gptr.Of("dirac"),
+//	}
+//	numDeleted, err := userDAL.Delete(ctx, where)
+//
+// SQL:
+// UPDATE `user` SET `is_deleted`=true WHERE `name` = 'dirac' AND `is_deleted` = false
+func (gdal *GDAL[PO, Where, Update]) Delete(ctx context.Context, where *Where, options ...QueryOption) (int64, error) {
+	return gdal.delete(ctx, where, options...)
+}
+
+// DeleteByID deletes by primary key, and return success count
+//
+// 💡 HINT: ref Delete and QueryByID.
 //
 // ⚠️  WARNING:
 //
 // 🚀 example:
-func (gdal *GDAL[PO, Where, Update]) Delete(ctx context.Context, where *Where) (int64, error) {
-	return gdal.DAL.Delete(ctx, gdal.MakePO(), where)
+//
+//	numDeleted, err := userDAL.DeleteByID(ctx, 123)
+//
+// SQL:
+// UPDATE `user` SET `is_deleted`=true WHERE `id` = 123 AND `is_deleted` = false
+func (gdal *GDAL[PO, Where, Update]) DeleteByID(ctx context.Context, id any, options ...QueryOption) (int64, error) {
+	where, err := gdal.pkWhere(id)
+	if err != nil {
+		return 0, err
+	}
+	return gdal.delete(ctx, where, options...)
 }
 
-// DeleteByID deletes physically by primary key
+// HardDelete deletes physically by condition, and return success count
 //
-// 💡 HINT:
+// 💡 HINT: soft-deleted records are also deleted.
 //
 // ⚠️  WARNING:
 //
 // 🚀 example:
-func (gdal *GDAL[PO, Where, Update]) DeleteByID(ctx context.Context, id int64) (int64, error) {
-	return gdal.DAL.Delete(ctx, gdal.MakePO(), idWhere{ID: &id})
+//
+//	where := &UserWhere {
+//		Name: gptr.Of("dirac"),
+//	}
+//	numDeleted, err := userDAL.HardDelete(ctx, where)
+//
+// SQL:
+// DELETE FROM `user` WHERE `name` = 'dirac'
+func (gdal *GDAL[PO, Where, Update]) HardDelete(ctx context.Context, where *Where, options ...QueryOption) (int64, error) {
+	return gdal.extended().DeleteWithOptions(ctx, gdal.MakePO(), where, options...)
+}
+
+// Restore restores soft-deleted records by condition, and return success count
+//
+// 💡 HINT: the soft-delete column is set to `false` or `0`, and the version is increased by 1 if PO has
+// version column.
+//
+// ⚠️  WARNING: PO must have a field tagged by `gdal:"soft_delete"`. InjectDefaulter of where is not
+// applied, because it is usually used to filter out deleted records.
+//
+// 🚀 example:
+//
+//	where := &UserWhere {
+//		Name: gptr.Of("dirac"),
+//	}
+//	numRestored, err := userDAL.Restore(ctx, where)
+//
+// SQL:
+// UPDATE `user` SET `is_deleted`=false WHERE `name` = 'dirac' AND `is_deleted` = true
+func (gdal *GDAL[PO, Where, Update]) Restore(ctx context.Context, where *Where, options ...QueryOption) (int64, error) {
+	meta, err := getPOMeta[PO]()
+	if err != nil {
+		return 0, err
+	}
+	if len(meta.softDelete) == 0 {
+		return 0, gerror.GDALErrorf("can not restore model (%v) without soft-delete column", meta.structType)
+	}
+	options = append(options, withExprs(meta.softDeleteExpr(true)))
+	update := meta.incrVersionIfHas(map[string]any{meta.softDelete: meta.softDeleteValue(false)})
+	return gdal.extended().UpdateWithOptions(ctx, gdal.MakePO(), where, update, options...)
 }
 
 // WithTx generate a new GDAL with tx embedded
@@ -509,7 +900,7 @@ func (gdal *GDAL[PO, Where, Update]) DeleteByID(ctx context.Context, id int64) (
 //
 // 🚀 example:
 func (gdal *GDAL[PO, Where, Update]) WithTx(tx *gorm.DB) *GDAL[PO, Where, Update] {
-	return NewGDAL[PO, Where, Update](tx)
+	return gdal.withDB(tx)
 }
 
 // DBWithCtx get embedded DB with context
@@ -543,9 +934,79 @@ func (gdal *GDAL[PO, Where, Update]) DB(options ...QueryOption) *gorm.DB {
 // 🚀 example:
 func (gdal *GDAL[PO, Where, Update]) Clauses(conds ...clause.Expression) *GDAL[PO, Where, Update] {
 	tx := gdal.DB().Clauses(conds...)
+	return gdal.withDB(tx)
+}
+
+// Use generate a new GDAL with interceptors appended, which intercept every operation after the global ones.
+//
+// 💡 HINT: ref Interceptor.
+//
+// ⚠️  WARNING: only effective for the DAL built by NewDAL.
+//
+// 🚀 example:
+//
+//	userDAL := gdal.NewGDAL[model.User, model.UserWhere, model.UserUpdate](db).Use(metricsInterceptor)
+func (gdal *GDAL[PO, Where, Update]) Use(interceptors ...Interceptor) *GDAL[PO, Where, Update] {
+	cli, ok := gdal.DAL.(*dal)
+	if !ok {
+		return gdal
+	}
+	cli = cli.withDB(cli.db).(*dal)
+	cli.interceptors = append(cli.interceptors[:len(cli.interceptors):len(cli.interceptors)], interceptors...)
+	return &GDAL[PO, Where, Update]{cli}
+}
+
+// withDB generate a new GDAL with tx embedded, the interceptors reserved.
+func (gdal *GDAL[PO, Where, Update]) withDB(tx *gorm.DB) *GDAL[PO, Where, Update] {
+	if cli, ok := gdal.DAL.(*dal); ok {
+		return &GDAL[PO, Where, Update]{cli.withDB(tx)}
+	}
 	return NewGDAL[PO, Where, Update](tx)
 }
 
+// extended the extended operations of the embedded DAL.
+//
+// 💡 HINT: a DAL implemented elsewhere without them only supports Delete and Update without options, and
+// MCreate by its DB.
+func (gdal *GDAL[PO, Where, Update]) extended() extendedDAL {
+	if ext, ok := gdal.DAL.(extendedDAL); ok {
+		return ext
+	}
+	return basicDAL{gdal.DAL}
+}
+
+// basicDAL adapt a DAL to extendedDAL as far as DAL supports.
+type basicDAL struct {
+	DAL
+}
+
+func (d basicDAL) MCreate(ctx context.Context, pos any, batchSize int) (int64, error) {
+	tx := d.DAL.DBWithCtx(ctx).CreateInBatches(pos, batchSize)
+	return tx.RowsAffected, tx.Error
+}
+
+func (d basicDAL) DeleteWithOptions(ctx context.Context, po any, where any, options ...QueryOption) (int64, error) {
+	if len(options) > 0 {
+		return 0, gerror.GDALErrorf("%T does not support Delete with options", d.DAL)
+	}
+	return d.DAL.Delete(ctx, po, where)
+}
+
+func (d basicDAL) UpdateWithOptions(ctx context.Context, po any, where any, update any, options ...QueryOption) (int64, error) {
+	if len(options) > 0 {
+		return 0, gerror.GDALErrorf("%T does not support Update with options", d.DAL)
+	}
+	return d.DAL.Update(ctx, po, where, update)
+}
+
+func (d basicDAL) Aggregate(ctx context.Context, po any, where any, result any, options ...QueryOption) error {
+	return gerror.GDALErrorf("%T does not support Aggregate", d.DAL)
+}
+
+func (d basicDAL) Pluck(ctx context.Context, po any, where any, column string, values any, options ...QueryOption) error {
+	return gerror.GDALErrorf("%T does not support Pluck", d.DAL)
+}
+
 func buildQueryOptions(limit *int64, offset *int64, order *string) []QueryOption {
 	var options []QueryOption
 	if limit != nil {
@@ -560,11 +1021,76 @@ func buildQueryOptions(limit *int64, offset *int64, order *string) []QueryOption
 	return options
 }
 
-type idWhere struct {
-	ID       *int64   `sql_field:"id" sql_operator:"="`
-	IDMustIn *[]int64 `sql_field:"id" sql_operator:"in"`
+// buildOnConflict build the conflict clause applying the non-nil fields of update.
+func (gdal *GDAL[PO, Where, Update]) buildOnConflict(conflictColumns []string, update *Update) (clause.OnConflict, error) {
+	meta, err := getPOMeta[PO]()
+	if err != nil {
+		return clause.OnConflict{}, err
+	}
+	if len(conflictColumns) == 0 {
+		conflictColumns = meta.primaryKeys
+	}
+	var onConflict clause.OnConflict
+	for _, column := range conflictColumns {
+		if !meta.hasColumn(column) {
+			return clause.OnConflict{}, gerror.ColumnNotFoundErr(meta.structType, column)
+		}
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: column})
+	}
+
+	var attrs map[string]any
+	if update != nil {
+		attrs, err = gsql.BuildSQLUpdate(update)
+		if err != nil {
+			return clause.OnConflict{}, err
+		}
+	}
+	if len(attrs) == 0 {
+		onConflict.DoNothing = true
+		return onConflict, nil
+	}
+	if guard, _, err := gsql.BuildSQLUpdateGuard(update); err != nil || guard != nil {
+		if err != nil {
+			return clause.OnConflict{}, err
+		}
+		return clause.OnConflict{}, gerror.GDALErrorf("sql_min of model (%v) is not supported by upsert", meta.structType)
+	}
+	if _, ok := attrs[meta.version]; ok && len(meta.version) > 0 {
+		return clause.OnConflict{}, gerror.GDALErrorf("version column (%s) can not be checked by upsert", meta.version)
+	}
+	attrs = meta.incrVersionIfHas(attrs)
+	onConflict.DoUpdates = clause.Assignments(attrs)
+	sort.Slice(onConflict.DoUpdates, func(i, j int) bool { // map is unordered, sort for stable SQL
+		return onConflict.DoUpdates[i].Column.Name < onConflict.DoUpdates[j].Column.Name
+	})
+	return onConflict, nil
+}
+
+// checkWhereNotEmpty the where struct of caller must not be empty, so that it never matches every record.
+func checkWhereNotEmpty(where any, action string) error {
+	expr, err := buildStructWhereExpr(where)
+	if err != nil {
+		return err
+	}
+	if isEmptyExpr(expr) {
+		return gerror.GDALErrorf("can not %s without args", action)
+	}
+	return nil
 }
 
-func (where *idWhere) ForceIndex() string {
-	return "PRIMARY"
+// delete deletes logically if PO has soft-delete column, otherwise, deletes physically.
+func (gdal *GDAL[PO, Where, Update]) delete(ctx context.Context, where any, options ...QueryOption) (int64, error) {
+	meta, err := getPOMeta[PO]()
+	if err != nil {
+		return 0, err
+	}
+	if len(meta.softDelete) == 0 {
+		return gdal.extended().DeleteWithOptions(ctx, gdal.MakePO(), where, options...)
+	}
+	options, err = gdal.scopeIfSoftDelete(options) // no need to delete the deleted records again.
+	if err != nil {
+		return 0, err
+	}
+	update := meta.incrVersionIfHas(map[string]any{meta.softDelete: meta.softDeleteValue(true)})
+	return gdal.extended().DeleteWithOptions(ctx, gdal.MakePO(), where, append(options, withSoftDelete(update))...)
 }
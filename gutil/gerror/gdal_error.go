@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 var (
@@ -33,3 +34,97 @@ func GormTagShouldBeKVsErr(tag string) error {
 func GetSelectorFromNonStructErr(rt reflect.Type) error {
 	return GDALErrorf("could not get selector from non-struct type: %v", rt)
 }
+
+func ColumnNotFoundErr(rt reflect.Type, column string) error {
+	return GDALErrorf("column (%s) not found in model (%v)", column, rt)
+}
+
+func InvalidCursorErr(cursor string) error {
+	return GDALErrorf("invalid cursor: %q", cursor)
+}
+
+func NullableSortColumnErr(rt reflect.Type, column string) error {
+	return GDALErrorf("sort column (%s) of model (%v) is nullable, which can not be used by cursor paging", column, rt)
+}
+
+func GDALTagInvalidErr(fieldName string, option string) error {
+	return GDALErrorf("field (%s) with gdal tag (%s) invalid", fieldName, option)
+}
+
+func LockingWithoutTxErr(locking string) error {
+	return GDALErrorf("row locking (FOR %s) must be used in transaction", locking)
+}
+
+func LockingNotSupportedErr(locking string, operation string) error {
+	return GDALErrorf("row locking (FOR %s) is not supported by %s", locking, operation)
+}
+
+// VersionConflictError the record was modified concurrently, so that the expected version mismatched.
+type VersionConflictError struct {
+	Model   reflect.Type
+	Version any // the expected version
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%v: version conflict of model (%v), expected version: %v", GDALErr, e.Model, e.Version)
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return GDALErr
+}
+
+func VersionConflictErr(rt reflect.Type, version any) error {
+	return &VersionConflictError{Model: rt, Version: version}
+}
+
+func IsVersionConflictErr(err error) bool {
+	var conflictErr *VersionConflictError
+	return errors.As(err, &conflictErr)
+}
+
+// InsufficientValueError no record qualified the floors of guarded columns, such as a balance less than
+// the amount to deduct.
+type InsufficientValueError struct {
+	Model   reflect.Type
+	Columns []string // the guarded columns tagged by `sql_min`
+}
+
+func (e *InsufficientValueError) Error() string {
+	return fmt.Sprintf("%v: insufficient value of columns (%s) in model (%v)", GDALErr, strings.Join(e.Columns, ", "), e.Model)
+}
+
+func (e *InsufficientValueError) Unwrap() error {
+	return GDALErr
+}
+
+func InsufficientValueErr(rt reflect.Type, columns []string) error {
+	return &InsufficientValueError{Model: rt, Columns: columns}
+}
+
+func IsInsufficientValueErr(err error) bool {
+	var insufficientErr *InsufficientValueError
+	return errors.As(err, &insufficientErr)
+}
+
+// MissingIDsError some of the primary keys queried in strict mode are not found.
+type MissingIDsError struct {
+	Model reflect.Type
+	IDs   []any // the missing primary keys, in the order of input
+}
+
+func (e *MissingIDsError) Error() string {
+	return fmt.Sprintf("%v: %d primary keys of model (%v) not found: %v", GDALErr, len(e.IDs), e.Model, e.IDs)
+}
+
+func (e *MissingIDsError) Unwrap() error {
+	return GDALErr
+}
+
+func MissingIDsErr(rt reflect.Type, ids []any) error {
+	return &MissingIDsError{Model: rt, IDs: ids}
+}
+
+func IsMissingIDsErr(err error) bool {
+	var missingErr *MissingIDsError
+	return errors.As(err, &missingErr)
+}
@@ -20,6 +20,9 @@ type sqlColumn struct {
 	Field       string       // tag sql_field
 	Operator    string       // tag sql_operator
 	Expr        string       // tag sql_expr
+	Select      string       // tag sql_select, column projected by subquery
+	Sensitive   bool         // tag sql_sensitive:"true"
+	Min         any          // tag sql_min, the floor of updated value, nil if absent
 	IsAnonymous bool         // field 是否是匿名字段
 	Kind        reflect.Kind // field Kind
 }
@@ -57,7 +60,7 @@ func parseTypeSlow(structType reflect.Type) (_ *sqlType, err error) {
 			return nil, fmt.Errorf("field(%s) column not found", name)
 		}
 		if column.Expr != "" {
-			if _, ok := updaterMap[column.Expr]; !ok {
+			if _, _, ok := getUpdater(column.Expr); !ok {
 				return nil, fmt.Errorf("field(%s) operator(%s) invalid", column.Name, column.Operator)
 			}
 		}
@@ -83,11 +86,14 @@ func parseTypeRev(structType reflect.Type, sType *sqlType, isField bool) (_ *sql
 		sqlField := strings.TrimSpace(structField.Tag.Get("sql_field"))
 		sqlOperator := strings.TrimSpace(structField.Tag.Get("sql_operator"))
 		sqlExpr := strings.TrimSpace(structField.Tag.Get("sql_expr"))
+		sqlSelect := strings.TrimSpace(structField.Tag.Get("sql_select"))
+		sqlSensitive := strings.TrimSpace(structField.Tag.Get("sql_sensitive"))
+		sqlMin := strings.TrimSpace(structField.Tag.Get("sql_min"))
 		// 忽略 tag
-		if sqlField == "-" || (sqlField == "" && sqlExpr == "$or") {
+		if sqlField == "-" || (sqlField == "" && isGroupExpr(sqlExpr)) {
 			continue
 		}
-		if sqlExpr == "$or" {
+		if isGroupExpr(sqlExpr) {
 			return nil, fmt.Errorf("struct field(%s) with mix of sql_field(%v) and expr(%s) invalid", structField.Name, sqlField, sqlExpr)
 		}
 		if err := checkField(structField, sqlField); err != nil {
@@ -110,14 +116,24 @@ func parseTypeRev(structType reflect.Type, sType *sqlType, isField bool) (_ *sql
 				}
 			}
 		} else {
-			if sqlOperator != "" {
+			if isSubQueryOperator(sqlOperator) {
+				if err := checkSubQuery(structField, sqlOperator, sqlSelect); err != nil {
+					return nil, err
+				}
+			} else if sqlOperator != "" {
 				if err := checkOperator(structField, sqlOperator); err != nil {
 					return nil, err
 				}
 			}
 			if sqlExpr != "" {
-				if _, ok := updaterMap[sqlExpr]; !ok {
-					return nil, fmt.Errorf("field(%s) expr(%s) invalid", structField.Name, sqlExpr)
+				if err := checkUpdater(structField, sqlExpr); err != nil {
+					return nil, err
+				}
+			}
+			var min any
+			if sqlMin != "" {
+				if min, err = parseMin(structField, sqlExpr, sqlMin); err != nil {
+					return nil, err
 				}
 			}
 			column := &sqlColumn{
@@ -126,6 +142,9 @@ func parseTypeRev(structType reflect.Type, sType *sqlType, isField bool) (_ *sql
 				Field:       sqlField,
 				Operator:    sqlOperator,
 				Expr:        sqlExpr,
+				Select:      sqlSelect,
+				Sensitive:   sqlSensitive == "true",
+				Min:         min,
 				IsAnonymous: structField.Anonymous,
 				Kind:        structField.Type.Kind(),
 			}
@@ -157,7 +176,7 @@ func checkField(structField reflect.StructField, sqlField string) error {
 	}
 
 	if !structField.Anonymous {
-		if structField.Type.Kind() != reflect.Ptr && structField.Type.Kind() != reflect.Slice {
+		if structField.Type.Kind() != reflect.Ptr && structField.Type.Kind() != reflect.Slice && !isNullableType(structField.Type) {
 			return fmt.Errorf("struct field(%s) must be pointer, but got %s", structField.Name, structField.Type.Kind())
 		}
 		if sqlField == "" {
@@ -168,24 +187,57 @@ func checkField(structField reflect.StructField, sqlField string) error {
 	return nil
 }
 
+// checkUpdater the updater must be registered, and accept the type of field.
+func checkUpdater(field reflect.StructField, sqlExpr string) error {
+	_, opts, ok := getUpdater(sqlExpr)
+	if !ok {
+		return fmt.Errorf("field(%s) expr(%s) invalid", field.Name, sqlExpr)
+	}
+
+	valueType := field.Type
+	switch {
+	case isNullableType(valueType):
+		valueType = nullableValueType(valueType)
+	case valueType.Kind() == reflect.Slice:
+		if !opts.SupportSlice {
+			return fmt.Errorf("field(%s) expr(%s) does not accept slice", field.Name, sqlExpr)
+		}
+		valueType = valueType.Elem()
+	}
+	if valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+	if !opts.acceptKind(valueType.Kind()) {
+		return fmt.Errorf("field(%s) expr(%s) does not accept %s", field.Name, sqlExpr, valueType.Kind())
+	}
+	return nil
+}
+
 func checkOperator(field reflect.StructField, sqlOperator string) error {
-	if _, ok := whereMap[sqlOperator]; !ok {
+	_, opts, ok := getWhereOperator(sqlOperator)
+	if !ok {
 		return fmt.Errorf("field(%s) operator(%s) invalid", field.Name, sqlOperator)
 	}
 
+	valueType := field.Type
 	if field.Type.Kind() != reflect.Ptr {
 		// in 操作，是数组，不是指针
-		if field.Type.Kind() == reflect.Slice && isOperatorSupportArray(sqlOperator) {
-			// continue
+		if field.Type.Kind() == reflect.Slice && opts.SupportSlice {
+			valueType = field.Type.Elem()
 		} else if field.Type.Kind() == reflect.Slice && field.Name == "Select" {
-			// continue
+			return nil
 		} else {
 			return fmt.Errorf("struct field(%s) must be pointer, but got %s", field.Name, field.Type.Kind())
 		}
 	}
+	if valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+	if !opts.acceptKind(valueType.Kind()) {
+		return fmt.Errorf("field(%s) operator(%s) does not accept %s", field.Name, sqlOperator, valueType.Kind())
+	}
+	if !opts.acceptType(valueType) {
+		return fmt.Errorf("field(%s) operator(%s) does not accept %s", field.Name, sqlOperator, valueType)
+	}
 	return nil
 }
-
-func isOperatorSupportArray(s string) bool {
-	return s == "in" || s == "not in" || s == "json_contains" || s == "json_contains any" || s == "json_contains all"
-}
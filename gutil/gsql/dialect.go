@@ -0,0 +1,223 @@
+package gsql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// names of gorm.Dialector
+const (
+	DialectMySQL     = "mysql"
+	DialectPostgres  = "postgres"
+	DialectSQLite    = "sqlite"
+	DialectSQLServer = "sqlserver"
+)
+
+// DialectOf the name of the dialect building SQL, MySQL if unknown.
+//
+// 💡 HINT: builder is *gorm.Statement when the expression is built by gorm.
+func DialectOf(builder clause.Builder) string {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok || stmt.DB == nil || stmt.DB.Dialector == nil {
+		return DialectMySQL
+	}
+	return stmt.DB.Dialector.Name()
+}
+
+// dialectExpr expression generated by the dialect building it.
+type dialectExpr func(dialect string) clause.Expression
+
+func (expr dialectExpr) Build(builder clause.Builder) {
+	expr(DialectOf(builder)).Build(builder)
+}
+
+// columnOf column of sql_field, which can be qualified by table, such as `user.name`.
+//
+// 💡 HINT: the column is quoted by the dialect building it, rather than by MySQL backticks.
+func columnOf(field string) clause.Column {
+	if table, name, qualified := strings.Cut(field, "."); qualified {
+		return clause.Column{Table: table, Name: name}
+	}
+	return clause.Column{Name: field}
+}
+
+// updateColumnOf column of sql_field referred in the value of update, qualified by the current table if not
+// qualified yet, since it is ambiguous on conflict of upsert in Postgres and SQL Server.
+func updateColumnOf(field string) clause.Column {
+	column := columnOf(field)
+	if column.Table == "" {
+		column.Table = clause.CurrentTable
+	}
+	return column
+}
+
+// jsonArrayOf JSON text of data as an array, data is taken as JSON text if it is string or []byte,
+// and it is wrapped by an array if it is not an array yet.
+func jsonArrayOf(data any) string {
+	text := jsonOf(data)
+	if strings.HasPrefix(strings.TrimSpace(text), "[") {
+		return text
+	}
+	return "[" + text + "]"
+}
+
+// jsonOf JSON text of data, data is taken as JSON text if it is string or []byte.
+func jsonOf(data any) string {
+	switch v := data.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return marshalJSON(data)
+}
+
+// marshalJSON JSON text of value.
+func marshalJSON(value any) string {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(content)
+}
+
+// jsonSetExpr set the values of JSON keys of column by dialect.
+func jsonSetExpr(column clause.Column, keys []string, values []any, dialect string) clause.Expression {
+	switch dialect {
+	case DialectPostgres:
+		var expr clause.Expression = clause.Expr{SQL: "CAST(? AS jsonb)", Vars: []any{column}}
+		for i, key := range keys {
+			path := fmt.Sprintf("'{%s}'", strings.ReplaceAll(key, ".", ","))
+			expr = clause.Expr{SQL: "jsonb_set(?, " + path + ", CAST(? AS jsonb))", Vars: []any{expr, marshalJSON(values[i])}}
+		}
+		return expr
+	case DialectSQLServer:
+		var expr clause.Expression = clause.Expr{SQL: "?", Vars: []any{column}}
+		for i, key := range keys {
+			expr = clause.Expr{SQL: "JSON_MODIFY(?, '$." + key + "', ?)", Vars: []any{expr, values[i]}}
+		}
+		return expr
+	default: // MySQL and SQLite
+		var sql strings.Builder
+		sql.WriteString("JSON_SET(?")
+		for _, key := range keys {
+			sql.WriteString(", '$." + key + "', ?")
+		}
+		sql.WriteString(")")
+		return clause.Expr{SQL: sql.String(), Vars: append([]any{column}, values...)}
+	}
+}
+
+// jsonContainsExpr whether JSON column contains data by dialect.
+func jsonContainsExpr(column clause.Column, data any, dialect string) clause.Expression {
+	switch dialect {
+	case DialectPostgres:
+		return gorm.Expr("CAST(? AS jsonb) @> CAST(? AS jsonb)", column, jsonOf(data))
+	case DialectSQLite:
+		return gorm.Expr("NOT EXISTS (SELECT 1 FROM json_each(?) AS c WHERE c.value NOT IN (SELECT value FROM json_each(?)))",
+			jsonArrayOf(data), column)
+	case DialectSQLServer:
+		return gorm.Expr("NOT EXISTS (SELECT 1 FROM OPENJSON(?) AS c WHERE c.value NOT IN (SELECT value FROM OPENJSON(?)))",
+			jsonArrayOf(data), column)
+	default:
+		return gorm.Expr("JSON_CONTAINS(?, ?)", column, data)
+	}
+}
+
+// greatestExpr the greater of column and data by dialect.
+func greatestExpr(column clause.Column, data any, dialect string) clause.Expression {
+	switch dialect {
+	case DialectSQLite:
+		return gorm.Expr("MAX(?, ?)", column, data)
+	case DialectSQLServer: // GREATEST is unavailable before SQL Server 2022
+		return gorm.Expr("CASE WHEN ? < ? THEN ? ELSE ? END", column, data, data, column)
+	default:
+		return gorm.Expr("GREATEST(?, ?)", column, data)
+	}
+}
+
+// leastExpr the less of column and data by dialect.
+func leastExpr(column clause.Column, data any, dialect string) clause.Expression {
+	switch dialect {
+	case DialectSQLite:
+		return gorm.Expr("MIN(?, ?)", column, data)
+	case DialectSQLServer: // LEAST is unavailable before SQL Server 2022
+		return gorm.Expr("CASE WHEN ? > ? THEN ? ELSE ? END", column, data, data, column)
+	default:
+		return gorm.Expr("LEAST(?, ?)", column, data)
+	}
+}
+
+// concatExpr column appended by data by dialect.
+func concatExpr(column clause.Column, data any, dialect string) clause.Expression {
+	switch dialect {
+	case DialectPostgres, DialectSQLite:
+		return gorm.Expr("? || ?", column, data)
+	default:
+		return gorm.Expr("CONCAT(?, ?)", column, data)
+	}
+}
+
+// jsonRemoveExpr remove the keys of JSON column by dialect, keys are passed as parameters rather than SQL.
+func jsonRemoveExpr(column clause.Column, keys []string, dialect string) clause.Expression {
+	switch dialect {
+	case DialectPostgres:
+		var expr clause.Expression = clause.Expr{SQL: "CAST(? AS jsonb)", Vars: []any{column}}
+		for _, key := range keys {
+			path := "{" + strings.ReplaceAll(key, ".", ",") + "}"
+			expr = clause.Expr{SQL: "? #- CAST(? AS text[])", Vars: []any{expr, path}}
+		}
+		return expr
+	case DialectSQLServer:
+		var expr clause.Expression = clause.Expr{SQL: "?", Vars: []any{column}}
+		for _, key := range keys {
+			expr = clause.Expr{SQL: "JSON_MODIFY(?, ?, NULL)", Vars: []any{expr, "$." + key}}
+		}
+		return expr
+	default: // MySQL and SQLite
+		sql := "JSON_REMOVE(?" + strings.Repeat(", ?", len(keys)) + ")"
+		vars := []any{column}
+		for _, key := range keys {
+			vars = append(vars, "$."+key)
+		}
+		return clause.Expr{SQL: sql, Vars: vars}
+	}
+}
+
+// jsonArrayAppendExpr append values to JSON array column by dialect.
+func jsonArrayAppendExpr(column clause.Column, values []any, dialect string) clause.Expression {
+	switch dialect {
+	case DialectPostgres:
+		return gorm.Expr("CAST(? AS jsonb) || CAST(? AS jsonb)", column, marshalJSON(values))
+	case DialectSQLServer:
+		var expr clause.Expression = clause.Expr{SQL: "?", Vars: []any{column}}
+		for _, value := range values {
+			expr = clause.Expr{SQL: "JSON_MODIFY(?, 'append $', ?)", Vars: []any{expr, value}}
+		}
+		return expr
+	case DialectSQLite:
+		sql := "json_insert(?" + strings.Repeat(", '$[#]', ?", len(values)) + ")"
+		return clause.Expr{SQL: sql, Vars: append([]any{column}, values...)}
+	default:
+		sql := "JSON_ARRAY_APPEND(?" + strings.Repeat(", '$', ?", len(values)) + ")"
+		return clause.Expr{SQL: sql, Vars: append([]any{column}, values...)}
+	}
+}
+
+// jsonMergePatchExpr merge patch, which is JSON text, into JSON column by dialect.
+//
+// ⚠️  WARNING: `||` of jsonb in Postgres merges the top-level keys only, and does not remove keys of null.
+func jsonMergePatchExpr(column clause.Column, patch string, dialect string) clause.Expression {
+	switch dialect {
+	case DialectPostgres:
+		return gorm.Expr("CAST(? AS jsonb) || CAST(? AS jsonb)", column, patch)
+	case DialectSQLite:
+		return gorm.Expr("json_patch(?, ?)", column, patch)
+	default:
+		return gorm.Expr("JSON_MERGE_PATCH(?, ?)", column, patch)
+	}
+}
@@ -0,0 +1,94 @@
+package gsql
+
+import (
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	. "github.com/smartystreets/goconvey/convey"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/utils/tests"
+)
+
+// namedDialector dummy dialector with the name of dialect
+type namedDialector struct {
+	tests.DummyDialector
+	name string
+}
+
+func (d namedDialector) Name() string {
+	return d.name
+}
+
+// buildExpr build expr by dialect on table `users`
+func buildExpr(dialect string, expr clause.Expression) (string, []any) {
+	db, _ := gorm.Open(namedDialector{name: dialect}, nil)
+	stmt := &gorm.Statement{DB: db, Table: "users", Clauses: map[string]clause.Clause{}}
+	expr.Build(stmt)
+	return stmt.SQL.String(), stmt.Vars
+}
+
+func TestDialect(t *testing.T) {
+	PatchConvey(t.Name(), t, func() {
+		PatchConvey("quote columns by dialect", func() {
+			where, err := BuildSQLWhereExpr(struct {
+				Name      *string `sql_field:"u.name"`
+				ManagerID *bool   `sql_field:"manager_id" sql_operator:"null"`
+				IDIn      []int   `sql_field:"id" sql_operator:"in"`
+			}{
+				Name:      gptr.Of("chen"),
+				ManagerID: gptr.Of(true),
+				IDIn:      []int{1, 2},
+			})
+			So(err, ShouldBeNil)
+			sql, vars := buildExpr(DialectMySQL, where)
+			So(sql, ShouldEqual, "(`u`.`name` = ? AND `manager_id` IS NULL AND `id` IN (?,?))")
+			So(vars, ShouldResemble, []any{"chen", 1, 2})
+		})
+
+		PatchConvey("json_contains", func() {
+			expr := JSONContains("tags", `"go"`)
+
+			sql, vars := buildExpr(DialectMySQL, expr)
+			So(sql, ShouldEqual, "JSON_CONTAINS(`tags`, ?)")
+			So(vars, ShouldResemble, []any{`"go"`})
+
+			sql, vars = buildExpr(DialectPostgres, expr)
+			So(sql, ShouldEqual, "CAST(`tags` AS jsonb) @> CAST(? AS jsonb)")
+			So(vars, ShouldResemble, []any{`"go"`})
+
+			sql, vars = buildExpr(DialectSQLite, expr)
+			So(sql, ShouldEqual, "NOT EXISTS (SELECT 1 FROM json_each(?) AS c WHERE c.value NOT IN (SELECT value FROM json_each(`tags`)))")
+			So(vars, ShouldResemble, []any{`["go"]`})
+
+			sql, vars = buildExpr(DialectSQLServer, JSONContains("tags", []int{1, 2}))
+			So(sql, ShouldEqual, "NOT EXISTS (SELECT 1 FROM OPENJSON(?) AS c WHERE c.value NOT IN (SELECT value FROM OPENJSON(`tags`)))")
+			So(vars, ShouldResemble, []any{`[1,2]`})
+		})
+
+		PatchConvey("json_set", func() {
+			type data struct {
+				A *string `json:"a"`
+				B *int    `json:"b"`
+			}
+			expr := JSONSetDialectExpr("data", data{A: gptr.Of("x"), B: gptr.Of(1)})
+
+			sql, vars := buildExpr(DialectSQLite, expr)
+			So(sql, ShouldEqual, "JSON_SET(`users`.`data`, '$.a', ?, '$.b', ?)")
+			So(vars, ShouldResemble, []any{"x", 1})
+
+			sql, vars = buildExpr(DialectPostgres, expr)
+			So(sql, ShouldEqual, "jsonb_set(jsonb_set(CAST(`users`.`data` AS jsonb), '{a}', CAST(? AS jsonb)), '{b}', CAST(? AS jsonb))")
+			So(vars, ShouldResemble, []any{`"x"`, "1"})
+
+			sql, vars = buildExpr(DialectSQLServer, expr)
+			So(sql, ShouldEqual, "JSON_MODIFY(JSON_MODIFY(`users`.`data`, '$.a', ?), '$.b', ?)")
+			So(vars, ShouldResemble, []any{"x", 1})
+
+			So(JSONSetDialectExpr("data", data{}), ShouldBeNil)
+			So(JSONSetExpr("data", data{}), ShouldResemble, clause.Expr{})
+			So(JSONSetExpr("data", data{A: gptr.Of("x")}), ShouldResemble, clause.Expr{SQL: "JSON_SET(`data` , '$.a', ?)", Vars: []any{"x"}})
+		})
+	})
+}
@@ -0,0 +1,144 @@
+package gsql
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/dirac-lee/gdal/gutil/greflect"
+	"gorm.io/gorm/clause"
+)
+
+// BuildSQLUpdateGuard build the guard predicates of Update struct, which are the floors of fields tagged by
+// `sql_min`, so that the column is updated only if the result is no less than the floor. It returns nil if no
+// guarded field is set, and the guarded columns.
+//
+// 💡 HINT: tag `sql_min` only works with `sql_expr:"-"` and `sql_expr:"+"` on numeric fields, such as
+// `balance - 10` guarded by `balance >= 10` for floor 0.
+//
+// ⚠️  WARNING: it is the caller who combines the guard with Where, and tells an unqualified row by RowsAffected.
+//
+// 🚀 example:
+//
+//	type UserUpdate struct {
+//		BalanceMinus *int64 `sql_field:"balance" sql_expr:"-" sql_min:"0"`
+//	}
+//
+//	guard, columns, err := BuildSQLUpdateGuard(&UserUpdate{BalanceMinus: gptr.Of[int64](10)})
+//
+// SQL: UPDATE `user` SET `balance` = `balance` - 10 WHERE ... AND `balance` >= 10
+func BuildSQLUpdateGuard(update any) (clause.Expression, []string, error) {
+	rv, rt, err := greflect.GetElemValueTypeOfPtr(reflect.ValueOf(update))
+	if err != nil {
+		return nil, nil, err
+	}
+	sqlType, err := parseType(rt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var exprs []clause.Expression
+	var columns []string
+	for _, name := range sqlType.Names {
+		column := sqlType.ColumnsMap[name]
+		if column.Min == nil {
+			continue
+		}
+		data := reflect.Indirect(rv.FieldByName(column.Name))
+		if !data.IsValid() {
+			continue
+		}
+		if nullable, ok := data.Interface().(nullabler); ok {
+			value, _ := nullable.nullableValue()
+			if value == nil { // unset or NULL
+				continue
+			}
+			data = reflect.ValueOf(value)
+		}
+		floor, guarded := floorOf(column.Expr, reflect.ValueOf(column.Min), data)
+		if !guarded {
+			continue
+		}
+		exprs = append(exprs, clause.Gte{Column: columnOf(column.Field), Value: floor})
+		columns = append(columns, column.Field)
+	}
+	if len(exprs) == 0 {
+		return nil, nil, nil
+	}
+	return clause.And(exprs...), columns, nil
+}
+
+// parseMin parse tag sql_min into the value type of field, which must be numeric with expr `+` or `-`.
+func parseMin(field reflect.StructField, sqlExpr, sqlMin string) (any, error) {
+	if sqlExpr != "+" && sqlExpr != "-" {
+		return nil, fmt.Errorf("field(%s) sql_min needs expr + or -, but got expr(%s)", field.Name, sqlExpr)
+	}
+	valueType := field.Type
+	if isNullableType(valueType) {
+		valueType = nullableValueType(valueType)
+	}
+	if valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+
+	min := reflect.New(valueType).Elem()
+	var err error
+	switch {
+	case min.CanInt():
+		var v int64
+		if v, err = strconv.ParseInt(sqlMin, 10, 64); err == nil {
+			min.SetInt(v)
+		}
+	case min.CanUint():
+		var v uint64
+		if v, err = strconv.ParseUint(sqlMin, 10, 64); err == nil {
+			min.SetUint(v)
+		}
+	case min.CanFloat():
+		var v float64
+		if v, err = strconv.ParseFloat(sqlMin, 64); err == nil {
+			min.SetFloat(v)
+		}
+	default:
+		return nil, fmt.Errorf("field(%s) sql_min needs number, but got %s", field.Name, valueType.Kind())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("field(%s) sql_min(%s) invalid: %w", field.Name, sqlMin, err)
+	}
+	return min.Interface(), nil
+}
+
+// floorOf the floor of column before update, i.e. min + data for `-`, and min - data for `+`.
+// It is not guarded if the floor is unreachable, such as unsigned `+`.
+func floorOf(expr string, min, data reflect.Value) (any, bool) {
+	if data.Type() != min.Type() {
+		if !data.CanConvert(min.Type()) {
+			return nil, false
+		}
+		data = data.Convert(min.Type())
+	}
+	floor := reflect.New(min.Type()).Elem()
+	switch {
+	case min.CanInt():
+		if expr == "-" {
+			floor.SetInt(min.Int() + data.Int())
+		} else {
+			floor.SetInt(min.Int() - data.Int())
+		}
+	case min.CanUint():
+		if expr == "-" {
+			floor.SetUint(min.Uint() + data.Uint())
+		} else if min.Uint() > data.Uint() {
+			floor.SetUint(min.Uint() - data.Uint())
+		} else {
+			return nil, false // always no less than min
+		}
+	default:
+		if expr == "-" {
+			floor.SetFloat(min.Float() + data.Float())
+		} else {
+			floor.SetFloat(min.Float() - data.Float())
+		}
+	}
+	return floor.Interface(), true
+}
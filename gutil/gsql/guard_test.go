@@ -0,0 +1,73 @@
+package gsql
+
+import (
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBuildSQLUpdateGuard(t *testing.T) {
+	PatchConvey(t.Name(), t, func() {
+		type UpdateUser struct {
+			Name         *string        `sql_field:"name"`
+			BalanceMinus *int64         `sql_field:"balance" sql_expr:"-" sql_min:"0"`
+			StockMinus   Nullable[uint] `sql_field:"stock" sql_expr:"-" sql_min:"1"`
+			StockPlus    *uint          `sql_field:"stock" sql_expr:"+" sql_min:"10"`
+			ScoreAdd     *float64       `sql_field:"score" sql_expr:"+" sql_min:"-1.5"`
+		}
+
+		PatchConvey("no guarded field is set", func() {
+			guard, columns, err := BuildSQLUpdateGuard(&UpdateUser{Name: gptr.Of("dirac"), StockMinus: Null[uint]()})
+			So(err, ShouldBeNil)
+			So(guard, ShouldBeNil)
+			So(columns, ShouldBeEmpty)
+		})
+
+		PatchConvey("floors of - and +", func() {
+			guard, columns, err := BuildSQLUpdateGuard(&UpdateUser{
+				BalanceMinus: gptr.Of[int64](10),
+				StockMinus:   NullableOf[uint](2),
+				ScoreAdd:     gptr.Of(-0.5),
+			})
+			So(err, ShouldBeNil)
+			So(columns, ShouldResemble, []string{"balance", "stock", "score"})
+			query, args := buildClauses(guard)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE (`balance` >= ? AND `stock` >= ? AND `score` >= ?)")
+			So(args, ShouldResemble, []any{int64(10), uint(3), -1.0})
+		})
+
+		PatchConvey("unsigned + never goes below the floor", func() {
+			guard, _, err := BuildSQLUpdateGuard(&UpdateUser{StockPlus: gptr.Of[uint](10)})
+			So(err, ShouldBeNil)
+			So(guard, ShouldBeNil)
+
+			guard, _, err = BuildSQLUpdateGuard(&UpdateUser{StockPlus: gptr.Of[uint](4)})
+			So(err, ShouldBeNil)
+			query, args := buildClauses(guard)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE `stock` >= ?")
+			So(args, ShouldResemble, []any{uint(6)})
+		})
+
+		PatchConvey("invalid sql_min", func() {
+			_, _, err := BuildSQLUpdateGuard(&struct {
+				Name *string `sql_field:"name" sql_expr:"concat" sql_min:"0"`
+			}{})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "field(Name) sql_min needs expr + or -, but got expr(concat)")
+
+			_, _, err = BuildSQLUpdateGuard(&struct {
+				Stock *uint `sql_field:"stock" sql_expr:"-" sql_min:"-1"`
+			}{})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "field(Stock) sql_min(-1) invalid")
+
+			_, _, err = BuildSQLUpdateGuard(&struct {
+				Name *string `sql_field:"name" sql_expr:"+" sql_min:"0"`
+			}{})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "field(Name) sql_min needs number, but got string")
+		})
+	})
+}
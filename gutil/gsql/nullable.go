@@ -0,0 +1,84 @@
+package gsql
+
+import (
+	"reflect"
+)
+
+var nullablerType = reflect.TypeOf((*nullabler)(nil)).Elem()
+
+// Nullable the value of Update field which can be set to NULL, with three states: unset, null and value.
+//
+// 💡 HINT: the zero value is unset, i.e. the column is left unchanged. Use Null to set the column to NULL, and
+// NullableOf to set it to a value. The field can be either `gsql.Nullable[T]` or `*gsql.Nullable[T]`, which is
+// also unset if nil.
+//
+// 💡 HINT: with tag `sql_expr`, the value is passed to the updater, while NULL is set directly.
+//
+// 🚀 example:
+//
+//	type UserUpdate struct {
+//		ManagerID gsql.Nullable[uint] `sql_field:"manager_id"`
+//	}
+//
+//	update := &UserUpdate{ManagerID: gsql.Null[uint]()}
+//
+// SQL: UPDATE `user` SET `manager_id` = NULL WHERE ...
+type Nullable[T any] struct {
+	value T
+	set   bool
+	null  bool
+}
+
+// Null set the column to NULL.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{set: true, null: true}
+}
+
+// NullableOf set the column to value.
+func NullableOf[T any](value T) Nullable[T] {
+	return Nullable[T]{value: value, set: true}
+}
+
+// IsSet whether the column is set, either to NULL or to a value.
+func (n Nullable[T]) IsSet() bool {
+	return n.set
+}
+
+// IsNull whether the column is set to NULL.
+func (n Nullable[T]) IsNull() bool {
+	return n.set && n.null
+}
+
+// Get the value set, false if unset or NULL.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.value, n.set && !n.null
+}
+
+// nullabler the state of Nullable
+type nullabler interface {
+	nullableValue() (value any, set bool)
+}
+
+// nullableValue nil if NULL
+func (n Nullable[T]) nullableValue() (any, bool) {
+	if !n.set || n.null {
+		return nil, n.set
+	}
+	return n.value, true
+}
+
+func isNullableType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Implements(nullablerType)
+}
+
+// nullableValueType the type of value T of Nullable[T] or *Nullable[T]
+func nullableValueType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	field, _ := t.FieldByName("value")
+	return field.Type
+}
@@ -0,0 +1,88 @@
+package gsql
+
+import (
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	. "github.com/smartystreets/goconvey/convey"
+	"gorm.io/gorm/clause"
+)
+
+func TestNullable(t *testing.T) {
+	PatchConvey(t.Name(), t, func() {
+		type UpdateUser struct {
+			Name      *string           `sql_field:"name"`
+			ManagerID Nullable[uint]    `sql_field:"manager_id"`
+			Nickname  *Nullable[string] `sql_field:"nickname"`
+			AgeIncr   Nullable[int]     `sql_field:"age" sql_expr:"+"`
+		}
+
+		PatchConvey("states", func() {
+			var unset Nullable[uint]
+			So(unset.IsSet(), ShouldBeFalse)
+			So(unset.IsNull(), ShouldBeFalse)
+
+			null := Null[uint]()
+			So(null.IsSet(), ShouldBeTrue)
+			So(null.IsNull(), ShouldBeTrue)
+			_, ok := null.Get()
+			So(ok, ShouldBeFalse)
+
+			value := NullableOf[uint](0)
+			So(value.IsSet(), ShouldBeTrue)
+			So(value.IsNull(), ShouldBeFalse)
+			v, ok := value.Get()
+			So(ok, ShouldBeTrue)
+			So(v, ShouldEqual, 0)
+		})
+
+		PatchConvey("unset is left unchanged", func() {
+			m, err := BuildSQLUpdate(&UpdateUser{Name: gptr.Of("dirac")})
+			So(err, ShouldBeNil)
+			So(m, ShouldResemble, map[string]any{"name": "dirac"})
+		})
+
+		PatchConvey("set to NULL", func() {
+			null := Null[string]()
+			m, err := BuildSQLUpdate(&UpdateUser{ManagerID: Null[uint](), Nickname: &null, AgeIncr: Null[int]()})
+			So(err, ShouldBeNil)
+			So(m, ShouldResemble, map[string]any{"manager_id": nil, "nickname": nil, "age": nil})
+		})
+
+		PatchConvey("set to value", func() {
+			nickname := NullableOf("lee")
+			m, err := BuildSQLUpdate(&UpdateUser{ManagerID: NullableOf[uint](0), Nickname: &nickname, AgeIncr: NullableOf(1)})
+			So(err, ShouldBeNil)
+			So(m, ShouldHaveLength, 3)
+			So(m["manager_id"], ShouldEqual, uint(0))
+			So(m["nickname"], ShouldEqual, "lee")
+			sql, vars := buildExpr(DialectMySQL, m["age"].(clause.Expression))
+			So(sql, ShouldEqual, "`users`.`age` + ?")
+			So(vars, ShouldResemble, []any{1})
+		})
+
+		PatchConvey("where", func() {
+			type WhereUser struct {
+				ManagerID Nullable[uint] `sql_field:"manager_id"`
+			}
+			where, err := BuildSQLWhereExpr(&WhereUser{ManagerID: Null[uint]()})
+			So(err, ShouldBeNil)
+			query, _ := buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE `manager_id` IS NULL")
+
+			where, err = BuildSQLWhereExpr(&WhereUser{ManagerID: NullableOf[uint](1)})
+			So(err, ShouldBeNil)
+			query, args := buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE `manager_id` = ?")
+			So(args, ShouldResemble, []any{uint(1)})
+		})
+
+		PatchConvey("non-pointer fields other than Nullable are invalid", func() {
+			_, err := BuildSQLUpdate(&struct {
+				Name string `sql_field:"name"`
+			}{})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
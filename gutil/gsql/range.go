@@ -0,0 +1,117 @@
+package gsql
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var rangerType = reflect.TypeOf((*ranger)(nil)).Elem()
+
+// Range the range of column, which is the type of Where field tagged by `sql_operator:"between"` or
+// `sql_operator:"not between"`.
+//
+// 💡 HINT: either bound can be nil, i.e. unbounded, and the comparison of the other bound is generated, such as
+// `column >= Min` if Max is nil. The range is ignored if both bounds are nil.
+//
+// 💡 HINT: bounds are inclusive by default, i.e. `column BETWEEN Min AND Max`. Set MinExclusive or MaxExclusive
+// to exclude the bound, such as `column >= Min AND column < Max` for a time window.
+//
+// 🚀 example:
+//
+//	type UserWhere struct {
+//		Birthday *gsql.Range[time.Time] `sql_field:"birthday" sql_operator:"between"`
+//		Age      *gsql.Range[uint]      `sql_field:"age" sql_operator:"not between"`
+//	}
+//
+//	where := &UserWhere{
+//		Birthday: gsql.NewHalfOpenRange(begin, end),
+//		Age:      &gsql.Range[uint]{Min: gptr.Of[uint](18)},
+//	}
+//
+// SQL: SELECT * FROM `user` WHERE (`birthday` >= begin AND `birthday` < end) AND `age` < 18
+type Range[T any] struct {
+	Min          *T   // lower bound, unbounded if nil
+	Max          *T   // upper bound, unbounded if nil
+	MinExclusive bool // `column > Min` rather than `column >= Min`
+	MaxExclusive bool // `column < Max` rather than `column <= Max`
+}
+
+// NewRange new closed range [min, max], i.e. `column BETWEEN min AND max`.
+func NewRange[T any](min, max T) *Range[T] {
+	return &Range[T]{Min: &min, Max: &max}
+}
+
+// NewHalfOpenRange new half-open range [min, max), i.e. `column >= min AND column < max`, which is
+// handy for time windows.
+func NewHalfOpenRange[T any](min, max T) *Range[T] {
+	return &Range[T]{Min: &min, Max: &max, MaxExclusive: true}
+}
+
+// ranger the bounds of range, nil if unbounded
+type ranger interface {
+	rangeBounds() (min, max any, minExclusive, maxExclusive bool)
+}
+
+func (r Range[T]) rangeBounds() (min, max any, minExclusive, maxExclusive bool) {
+	if r.Min != nil {
+		min = *r.Min
+	}
+	if r.Max != nil {
+		max = *r.Max
+	}
+	return min, max, r.MinExclusive, r.MaxExclusive
+}
+
+// buildRangeExpr `column BETWEEN min AND max`, or the comparisons of bounds, nil if both bounds are nil.
+func buildRangeExpr(operator string, column string, data any, negative bool) (clause.Expression, error) {
+	r, ok := data.(ranger)
+	if !ok {
+		return nil, fmt.Errorf("field with tag `%s` must be gsql.Range, but got %T", operator, data)
+	}
+	min, max, minExclusive, maxExclusive := r.rangeBounds()
+	col := columnOf(column)
+
+	var lower, upper clause.Expression
+	if min != nil {
+		if minExclusive {
+			lower = clause.Gt{Column: col, Value: min}
+		} else {
+			lower = clause.Gte{Column: col, Value: min}
+		}
+	}
+	if max != nil {
+		if maxExclusive {
+			upper = clause.Lt{Column: col, Value: max}
+		} else {
+			upper = clause.Lte{Column: col, Value: max}
+		}
+	}
+
+	switch {
+	case lower == nil && upper == nil:
+		return nil, nil
+	case lower != nil && upper != nil && !minExclusive && !maxExclusive:
+		if negative {
+			return gorm.Expr("? NOT BETWEEN ? AND ?", col, min, max), nil
+		}
+		return gorm.Expr("? BETWEEN ? AND ?", col, min, max), nil
+	case lower == nil:
+		if negative {
+			return clause.Not(upper), nil
+		}
+		return upper, nil
+	case upper == nil:
+		if negative {
+			return clause.Not(lower), nil
+		}
+		return lower, nil
+	default:
+		if negative {
+			return clause.Or(clause.Not(lower), clause.Not(upper)), nil
+		}
+		return clause.And(lower, upper), nil
+	}
+}
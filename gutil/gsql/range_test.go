@@ -0,0 +1,111 @@
+package gsql
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/bytedance/mockey"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRange(t *testing.T) {
+	PatchConvey(t.Name(), t, func() {
+		type WhereUser struct {
+			Name      *string           `sql_field:"name"`
+			Birthday  *Range[time.Time] `sql_field:"birthday" sql_operator:"between"`
+			AgeOut    *Range[uint]      `sql_field:"age" sql_operator:"not between"`
+			NameRange *Range[string]    `sql_field:"name" sql_operator:"between"`
+			OrClauses []WhereUser       `sql_expr:"$or"`
+		}
+		begin := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		PatchConvey("closed range", func() {
+			where, err := BuildSQLWhereExpr(&WhereUser{Birthday: NewRange(begin, end)})
+			So(err, ShouldBeNil)
+			query, args := buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE `birthday` BETWEEN ? AND ?")
+			So(args, ShouldResemble, []any{begin, end})
+
+			where, err = BuildSQLWhereExpr(&WhereUser{AgeOut: NewRange[uint](18, 60)})
+			So(err, ShouldBeNil)
+			query, args = buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE `age` NOT BETWEEN ? AND ?")
+			So(args, ShouldResemble, []any{uint(18), uint(60)})
+		})
+
+		PatchConvey("half-open range", func() {
+			where, err := BuildSQLWhereExpr(&WhereUser{
+				Name:     gptr.Of("dirac"),
+				Birthday: NewHalfOpenRange(begin, end),
+			})
+			So(err, ShouldBeNil)
+			query, args := buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE (`name` = ? AND (`birthday` >= ? AND `birthday` < ?))")
+			So(args, ShouldResemble, []any{"dirac", begin, end})
+
+			where, err = BuildSQLWhereExpr(&WhereUser{AgeOut: &Range[uint]{Min: gptr.Of[uint](18), Max: gptr.Of[uint](60), MinExclusive: true}})
+			So(err, ShouldBeNil)
+			query, args = buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE (`age` <= ? OR `age` > ?)")
+			So(args, ShouldResemble, []any{uint(18), uint(60)})
+		})
+
+		PatchConvey("one side", func() {
+			where, err := BuildSQLWhereExpr(&WhereUser{NameRange: &Range[string]{Min: gptr.Of("a")}})
+			So(err, ShouldBeNil)
+			query, _ := buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE `name` >= ?")
+
+			where, err = BuildSQLWhereExpr(&WhereUser{NameRange: &Range[string]{Max: gptr.Of("m"), MaxExclusive: true}})
+			So(err, ShouldBeNil)
+			query, _ = buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE `name` < ?")
+
+			where, err = BuildSQLWhereExpr(&WhereUser{AgeOut: &Range[uint]{Min: gptr.Of[uint](18)}})
+			So(err, ShouldBeNil)
+			query, _ = buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE `age` < ?")
+
+			where, err = BuildSQLWhereExpr(&WhereUser{AgeOut: &Range[uint]{Max: gptr.Of[uint](60), MaxExclusive: true}})
+			So(err, ShouldBeNil)
+			query, _ = buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE `age` >= ?")
+		})
+
+		PatchConvey("unbounded range is ignored", func() {
+			where, err := BuildSQLWhereExpr(&WhereUser{Name: gptr.Of("dirac"), Birthday: &Range[time.Time]{}})
+			So(err, ShouldBeNil)
+			query, _ := buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE `name` = ?")
+		})
+
+		PatchConvey("range in $or", func() {
+			where, err := BuildSQLWhereExpr(&WhereUser{
+				OrClauses: []WhereUser{
+					{Birthday: NewHalfOpenRange(begin, end)},
+					{AgeOut: NewRange[uint](18, 60)},
+				},
+			})
+			So(err, ShouldBeNil)
+			query, args := buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE ((`birthday` >= ? AND `birthday` < ?) OR (`age` NOT BETWEEN ? AND ?))")
+			So(args, ShouldResemble, []any{begin, end, uint(18), uint(60)})
+		})
+
+		PatchConvey("invalid range field", func() {
+			_, err := BuildSQLWhereExpr(&struct {
+				Birthday *time.Time `sql_field:"birthday" sql_operator:"between"`
+			}{})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "field(Birthday) operator(between) does not accept time.Time")
+		})
+
+		PatchConvey("sensitive bounds", func() {
+			values, err := SensitiveValues(&WhereUser{NameRange: NewRange("a", "m")}, "name")
+			So(err, ShouldBeNil)
+			So(values, ShouldResemble, []any{"a", "m"})
+		})
+	})
+}
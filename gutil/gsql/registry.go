@@ -0,0 +1,244 @@
+package gsql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm/clause"
+)
+
+var registryMu sync.RWMutex // guards whereMap, whereOptionsMap, updaterMap and updaterOptionsMap
+
+// WhereOperatorOptions the options of where operator, which are validated when Where struct is parsed.
+type WhereOperatorOptions struct {
+	SupportSlice bool           // whether field can be slice, such as `in`, otherwise, it must be pointer
+	Kinds        []reflect.Kind // kinds of value (or slice element) accepted, any kind if empty
+	Implements   reflect.Type   // interface the value (or slice element) must implement, such as gsql.Range, if not nil
+	Dialects     []string       // dialects supporting the operator, such as DialectMySQL, all dialects if empty
+}
+
+// whereOptionsMap the options of built-in operators, default options if absent
+var whereOptionsMap = map[string]WhereOperatorOptions{
+	"null":              {Kinds: []reflect.Kind{reflect.Bool}},
+	"in":                {SupportSlice: true},
+	"not in":            {SupportSlice: true},
+	"like":              {Kinds: []reflect.Kind{reflect.String}},
+	"left like":         {Kinds: []reflect.Kind{reflect.String}},
+	"right like":        {Kinds: []reflect.Kind{reflect.String}},
+	"full like":         {Kinds: []reflect.Kind{reflect.String}},
+	"json_contains":     {SupportSlice: true},
+	"json_contains any": {SupportSlice: true},
+	"json_contains all": {SupportSlice: true},
+	"between":           {Implements: rangerType},
+	"not between":       {Implements: rangerType},
+}
+
+// UpdaterOptions the options of updater, which are validated when Update struct is parsed.
+type UpdaterOptions struct {
+	SupportSlice bool           // whether field can be slice, such as `json_array_append`, otherwise, it must be pointer
+	Kinds        []reflect.Kind // kinds of value (or slice element) accepted, any kind if empty
+	Dialects     []string       // dialects supporting the updater, such as DialectMySQL, all dialects if empty
+}
+
+// numericKinds kinds of numbers
+var numericKinds = []reflect.Kind{
+	reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+	reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+	reflect.Float32, reflect.Float64,
+}
+
+// updaterOptionsMap the options of built-in updaters, default options if absent
+var updaterOptionsMap = map[string]UpdaterOptions{
+	"*":                 {Kinds: numericKinds},
+	"/":                 {Kinds: numericKinds},
+	"concat":            {Kinds: []reflect.Kind{reflect.String}},
+	"now":               {Kinds: []reflect.Kind{reflect.Bool}},
+	"from_column":       {Kinds: []reflect.Kind{reflect.String}},
+	"json_remove":       {SupportSlice: true, Kinds: []reflect.Kind{reflect.String}},
+	"json_array_append": {SupportSlice: true},
+	"json_merge_patch":  {Dialects: []string{DialectMySQL, DialectPostgres, DialectSQLite}},
+}
+
+// RegisterWhereOperator register the where operator used by tag `sql_operator`.
+//
+// 💡 HINT: register operators in init, before Where structs using them are parsed.
+//
+// ⚠️  WARNING: built-in and registered operators can not be overridden.
+//
+// 🚀 example:
+//
+//	err := gsql.RegisterWhereOperator("find_in_set", func(column string, data any) (clause.Expression, error) {
+//		return gorm.Expr("FIND_IN_SET(?, ?)", data, clause.Column{Name: column}), nil
+//	}, gsql.WhereOperatorOptions{
+//		Kinds:    []reflect.Kind{reflect.String},
+//		Dialects: []string{gsql.DialectMySQL},
+//	})
+//
+//	type UserWhere struct {
+//		Tag *string `sql_field:"tags" sql_operator:"find_in_set"`
+//	}
+func RegisterWhereOperator(name string, builder SQLWhereExprBuilder, opts WhereOperatorOptions) error {
+	if name == "" || builder == nil {
+		return errors.New("where operator must have name and builder")
+	}
+	if isSubQueryOperator(name) {
+		return fmt.Errorf("operator(%s) reserved", name)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := whereMap[name]; ok {
+		return fmt.Errorf("operator(%s) registered already", name)
+	}
+	whereMap[name] = builder
+	whereOptionsMap[name] = opts
+	return nil
+}
+
+// RegisterUpdater register the update expression used by tag `sql_expr`.
+//
+// 💡 HINT: register updaters in init, before Update structs using them are parsed. Use RegisterExprUpdater
+// to generate SQL by dialect.
+//
+// ⚠️  WARNING: built-in and registered updaters can not be overridden.
+//
+// 💡 HINT: opts is optional, and only the first one is used.
+//
+// 🚀 example:
+//
+//	err := gsql.RegisterUpdater("bit_or", func(column string, data any) clause.Expr {
+//		return gorm.Expr("? | ?", clause.Column{Name: column}, data)
+//	}, gsql.UpdaterOptions{
+//		Kinds: []reflect.Kind{reflect.Int64},
+//	})
+//
+//	type UserUpdate struct {
+//		FlagsSet *int64 `sql_field:"flags" sql_expr:"bit_or"`
+//	}
+func RegisterUpdater(name string, updater SQLUpdater, opts ...UpdaterOptions) error {
+	if updater == nil {
+		return errors.New("updater must have name and function")
+	}
+	return RegisterExprUpdater(name, func(column string, data any) clause.Expression {
+		if expr := updater(column, data); expr.SQL != "" {
+			return expr
+		}
+		return nil
+	}, opts...)
+}
+
+// RegisterExprUpdater register the update expression used by tag `sql_expr`, which returns any
+// clause.Expression, such as the one generated by dialect.
+//
+// 💡 HINT: ref RegisterUpdater.
+//
+// 🚀 example:
+//
+//	err := gsql.RegisterExprUpdater("bit_or", func(column string, data any) clause.Expression {
+//		return gorm.Expr("? | ?", clause.Column{Name: column}, data)
+//	})
+func RegisterExprUpdater(name string, updater SQLExprUpdater, opts ...UpdaterOptions) error {
+	if name == "" || updater == nil {
+		return errors.New("updater must have name and function")
+	}
+	if isGroupExpr(name) {
+		return fmt.Errorf("expr(%s) reserved", name)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := updaterMap[name]; ok {
+		return fmt.Errorf("expr(%s) registered already", name)
+	}
+	updaterMap[name] = updater
+	if len(opts) > 0 {
+		updaterOptionsMap[name] = opts[0]
+	}
+	return nil
+}
+
+func getWhereOperator(name string) (SQLWhereExprBuilder, WhereOperatorOptions, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	builder, ok := whereMap[name]
+	return builder, whereOptionsMap[name], ok
+}
+
+func getUpdater(name string) (SQLExprUpdater, UpdaterOptions, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	updater, ok := updaterMap[name]
+	return updater, updaterOptionsMap[name], ok
+}
+
+// acceptKind whether the operator accepts value of kind
+func (opts WhereOperatorOptions) acceptKind(kind reflect.Kind) bool {
+	return containsKind(opts.Kinds, kind)
+}
+
+// acceptKind whether the updater accepts value of kind
+func (opts UpdaterOptions) acceptKind(kind reflect.Kind) bool {
+	return containsKind(opts.Kinds, kind)
+}
+
+// containsKind whether kinds contains kind, true if kinds is empty
+func containsKind(kinds []reflect.Kind, kind reflect.Kind) bool {
+	if len(kinds) == 0 {
+		return true
+	}
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptType whether the operator accepts value of type t
+func (opts WhereOperatorOptions) acceptType(t reflect.Type) bool {
+	return opts.Implements == nil || t.Implements(opts.Implements)
+}
+
+// dialectChecked expr which fails to build on the dialects unsupported by operator
+func (opts WhereOperatorOptions) dialectChecked(operator string, expr clause.Expression) clause.Expression {
+	if len(opts.Dialects) == 0 {
+		return expr
+	}
+	return dialectExpr(func(dialect string) clause.Expression {
+		if containsDialect(opts.Dialects, dialect) {
+			return expr
+		}
+		return unsupportedExpr{err: fmt.Errorf("operator(%s) unsupported by dialect(%s)", operator, dialect)}
+	})
+}
+
+// dialectChecked expr which fails to build on the dialects unsupported by updater
+func (opts UpdaterOptions) dialectChecked(name string, expr clause.Expression) clause.Expression {
+	if len(opts.Dialects) == 0 {
+		return expr
+	}
+	return dialectExpr(func(dialect string) clause.Expression {
+		if containsDialect(opts.Dialects, dialect) {
+			return expr
+		}
+		return unsupportedExpr{err: fmt.Errorf("expr(%s) unsupported by dialect(%s)", name, dialect)}
+	})
+}
+
+func containsDialect(dialects []string, dialect string) bool {
+	for _, d := range dialects {
+		if d == dialect {
+			return true
+		}
+	}
+	return false
+}
+
+// unsupportedExpr expression adding err to builder
+type unsupportedExpr struct {
+	err error
+}
+
+func (expr unsupportedExpr) Build(builder clause.Builder) {
+	_ = builder.AddError(expr.err)
+}
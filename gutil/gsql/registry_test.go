@@ -0,0 +1,141 @@
+package gsql
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	. "github.com/smartystreets/goconvey/convey"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func TestRegistry(t *testing.T) {
+	findInSet := func(column string, data any) (clause.Expression, error) {
+		return gorm.Expr("FIND_IN_SET(?, ?)", data, clause.Column{Name: column}), nil
+	}
+	bitAnd := func(column string, data any) (clause.Expression, error) { // any of bits when slice
+		rv := reflect.ValueOf(data)
+		if rv.Kind() != reflect.Slice {
+			return gorm.Expr("? & ? != 0", clause.Column{Name: column}, data), nil
+		}
+		var exprs []clause.Expression
+		for i := 0; i < rv.Len(); i++ {
+			exprs = append(exprs, gorm.Expr("? & ? != 0", clause.Column{Name: column}, rv.Index(i).Interface()))
+		}
+		return clause.Or(exprs...), nil
+	}
+	bitOr := func(column string, data any) clause.Expr {
+		return gorm.Expr("? | ?", clause.Column{Name: column}, data)
+	}
+	bitXor := func(column string, data any) clause.Expression {
+		return gorm.Expr("? ^ ?", clause.Column{Name: column}, data)
+	}
+	// registry is global, register once rather than in every leaf convey
+	errFindInSet := RegisterWhereOperator("test find_in_set", findInSet, WhereOperatorOptions{
+		Kinds:    []reflect.Kind{reflect.String},
+		Dialects: []string{DialectMySQL},
+	})
+	errBitAnd := RegisterWhereOperator("test bit_and", bitAnd, WhereOperatorOptions{
+		SupportSlice: true,
+		Kinds:        []reflect.Kind{reflect.Int, reflect.Int64},
+	})
+	errBitOr := RegisterUpdater("test bit_or", bitOr)
+	errBitXor := RegisterExprUpdater("test bit_xor", bitXor, UpdaterOptions{Kinds: []reflect.Kind{reflect.Int64}})
+
+	PatchConvey(t.Name(), t, func() {
+		So(errFindInSet, ShouldBeNil)
+		So(errBitAnd, ShouldBeNil)
+		So(errBitOr, ShouldBeNil)
+		So(errBitXor, ShouldBeNil)
+
+		PatchConvey("registered where operator", func() {
+			where, err := BuildSQLWhereExpr(&struct {
+				Tag   *string `sql_field:"tags" sql_operator:"test find_in_set"`
+				Flags []int64 `sql_field:"flags" sql_operator:"test bit_and"`
+			}{
+				Tag:   gptr.Of("go"),
+				Flags: []int64{4, 8},
+			})
+			So(err, ShouldBeNil)
+			sql, vars := buildExpr(DialectMySQL, where)
+			So(sql, ShouldEqual, "(FIND_IN_SET(?, `tags`) AND (`flags` & ? != 0 OR `flags` & ? != 0))")
+			So(vars, ShouldResemble, []any{"go", int64(4), int64(8)})
+		})
+
+		PatchConvey("kinds are validated when parsed", func() {
+			_, err := BuildSQLWhereExpr(&struct {
+				Tag *int `sql_field:"tags" sql_operator:"test find_in_set"`
+			}{})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "field(Tag) operator(test find_in_set) does not accept int")
+
+			_, err = BuildSQLWhereExpr(&struct {
+				Name *int `sql_field:"name" sql_operator:"like"`
+			}{})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "field(Name) operator(like) does not accept int")
+		})
+
+		PatchConvey("slices are validated when parsed", func() {
+			_, err := BuildSQLWhereExpr(&struct {
+				Tags []string `sql_field:"tags" sql_operator:"test find_in_set"`
+			}{})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "must be pointer, but got slice")
+		})
+
+		PatchConvey("dialects are validated when built", func() {
+			where, err := BuildSQLWhereExpr(&struct {
+				Tag *string `sql_field:"tags" sql_operator:"test find_in_set"`
+			}{
+				Tag: gptr.Of("go"),
+			})
+			So(err, ShouldBeNil)
+			db, _ := gorm.Open(namedDialector{name: DialectPostgres}, nil)
+			stmt := &gorm.Statement{DB: db, Table: "users", Clauses: map[string]clause.Clause{}}
+			where.Build(stmt)
+			So(db.Error, ShouldNotBeNil)
+			So(db.Error.Error(), ShouldEqual, "operator(test find_in_set) unsupported by dialect(postgres)")
+		})
+
+		PatchConvey("registered updater", func() {
+			m, err := BuildSQLUpdate(&struct {
+				FlagsSet *int64 `sql_field:"flags" sql_expr:"test bit_or"`
+			}{
+				FlagsSet: gptr.Of[int64](8),
+			})
+			So(err, ShouldBeNil)
+			sql, vars := buildExpr(DialectMySQL, m["flags"].(clause.Expression))
+			So(sql, ShouldEqual, "`flags` | ?")
+			So(vars, ShouldResemble, []any{int64(8)})
+
+			m, err = BuildSQLUpdate(&struct {
+				FlagsFlip *int64 `sql_field:"flags" sql_expr:"test bit_xor"`
+			}{
+				FlagsFlip: gptr.Of[int64](4),
+			})
+			So(err, ShouldBeNil)
+			sql, vars = buildExpr(DialectMySQL, m["flags"].(clause.Expression))
+			So(sql, ShouldEqual, "`flags` ^ ?")
+			So(vars, ShouldResemble, []any{int64(4)})
+
+			_, err = BuildSQLUpdate(&struct {
+				FlagsSet *string `sql_field:"flags" sql_expr:"test bit_xor"`
+			}{})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "field(FlagsSet) expr(test bit_xor) does not accept string")
+		})
+
+		PatchConvey("can not override", func() {
+			So(RegisterWhereOperator("in", findInSet, WhereOperatorOptions{}), ShouldNotBeNil)
+			So(RegisterWhereOperator("test bit_and", bitAnd, WhereOperatorOptions{}), ShouldNotBeNil)
+			So(RegisterUpdater("+", bitOr), ShouldNotBeNil)
+			So(RegisterUpdater("$or", bitOr), ShouldNotBeNil)
+			So(RegisterWhereOperator("", findInSet, WhereOperatorOptions{}), ShouldNotBeNil)
+			So(RegisterUpdater("test nil", nil), ShouldNotBeNil)
+			So(RegisterExprUpdater("test nil", nil), ShouldNotBeNil)
+		})
+	})
+}
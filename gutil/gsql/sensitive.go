@@ -0,0 +1,109 @@
+package gsql
+
+import (
+	"reflect"
+
+	"github.com/dirac-lee/gdal/gutil/greflect"
+)
+
+// SensitiveValues collect the non-zero values of sensitive fields in Where or Update struct, so that they
+// can be redacted from logs and errors.
+//
+// 💡 HINT: a field is sensitive when it is tagged by `sql_sensitive:"true"`, or its sql_field is one of columns.
+// Elements of slice field, such as that of operator `in`, and bounds of gsql.Range are collected one by one.
+//
+// 🚀 example:
+//
+//	type UserWhere struct {
+//		Phone   *string  `sql_field:"phone" sql_sensitive:"true"`
+//		EmailIn []string `sql_field:"email" sql_operator:"in"`
+//	}
+//
+//	where := &UserWhere{
+//		Phone:   gptr.Of("13800000000"),
+//		EmailIn: []string{"a@b.c", "d@e.f"},
+//	}
+//	values, err := SensitiveValues(where, "email") // ["13800000000", "a@b.c", "d@e.f"]
+func SensitiveValues(v any, columns ...string) ([]any, error) {
+	rv, rt, err := greflect.GetElemValueTypeOfPtr(reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+	sensitiveColumns := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		sensitiveColumns[column] = true
+	}
+	return collectSensitiveValues(rv, rt, sensitiveColumns, nil)
+}
+
+func collectSensitiveValues(rv reflect.Value, rt reflect.Type, columns map[string]bool, values []any) ([]any, error) {
+	sqlType, err := parseType(rt)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range sqlType.Names {
+		column := sqlType.ColumnsMap[name]
+		if isSubQueryOperator(column.Operator) { // sensitive fields of Where in subquery
+			if querier, ok := rv.FieldByName(column.Name).Interface().(subQuerier); ok && !reflect.ValueOf(querier).IsNil() {
+				if where := querier.subQueryWhere(); where != nil {
+					values, err = collectGroupSensitiveValues(reflect.ValueOf(where), columns, values)
+					if err != nil {
+						return nil, err
+					}
+				}
+			}
+			continue
+		}
+		if !column.Sensitive && !columns[column.Field] {
+			continue
+		}
+		values = appendNonZeroValues(values, rv.FieldByName(column.Name))
+	}
+
+	for _, group := range getGroupList(rv, rt) { // sensitive fields in $or, $and and $not groups
+		values, err = collectGroupSensitiveValues(group.value, columns, values)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+func collectGroupSensitiveValues(rv reflect.Value, columns map[string]bool, values []any) ([]any, error) {
+	rv = reflect.Indirect(rv)
+	switch rv.Kind() {
+	case reflect.Struct:
+		return collectSensitiveValues(rv, rv.Type(), columns, values)
+	case reflect.Slice, reflect.Array:
+		var err error
+		for i := 0; i < rv.Len(); i++ {
+			if values, err = collectGroupSensitiveValues(rv.Index(i), columns, values); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return values, nil
+}
+
+func appendNonZeroValues(values []any, field reflect.Value) []any {
+	field = reflect.Indirect(field)
+	if !field.IsValid() || field.IsZero() {
+		return values
+	}
+	if n, ok := field.Interface().(nullabler); ok { // value of nullable
+		value, _ := n.nullableValue()
+		return appendNonZeroValues(values, reflect.ValueOf(value))
+	}
+	if r, ok := field.Interface().(ranger); ok { // bounds of range
+		min, max, _, _ := r.rangeBounds()
+		values = appendNonZeroValues(values, reflect.ValueOf(min))
+		return appendNonZeroValues(values, reflect.ValueOf(max))
+	}
+	if (field.Kind() == reflect.Slice || field.Kind() == reflect.Array) && field.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < field.Len(); i++ {
+			values = appendNonZeroValues(values, field.Index(i))
+		}
+		return values
+	}
+	return append(values, field.Interface())
+}
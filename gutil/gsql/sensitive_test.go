@@ -0,0 +1,58 @@
+package gsql
+
+import (
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSensitiveValues(t *testing.T) {
+	PatchConvey(t.Name(), t, func() {
+		type WhereUser struct {
+			Name    *string  `sql_field:"name"`
+			Phone   *string  `sql_field:"phone" sql_sensitive:"true"`
+			EmailIn []string `sql_field:"email" sql_operator:"in"`
+		}
+
+		PatchConvey("sql_sensitive tag", func() {
+			values, err := SensitiveValues(&WhereUser{
+				Name:  gptr.Of("chen"),
+				Phone: gptr.Of("13800000000"),
+			})
+			So(err, ShouldBeNil)
+			So(values, ShouldResemble, []any{"13800000000"})
+		})
+
+		PatchConvey("sensitive columns", func() {
+			values, err := SensitiveValues(&WhereUser{
+				Name:    gptr.Of("chen"),
+				EmailIn: []string{"a@b.c", "d@e.f"},
+			}, "email")
+			So(err, ShouldBeNil)
+			So(values, ShouldResemble, []any{"a@b.c", "d@e.f"})
+		})
+
+		PatchConvey("zero values are ignored", func() {
+			values, err := SensitiveValues(&WhereUser{Phone: gptr.Of("")}, "name")
+			So(err, ShouldBeNil)
+			So(values, ShouldBeEmpty)
+		})
+
+		PatchConvey("$or clauses", func() {
+			values, err := SensitiveValues(&struct {
+				Name      *string     `sql_field:"name"`
+				OrClauses []WhereUser `sql_expr:"$or"`
+			}{
+				Name: gptr.Of("chen"),
+				OrClauses: []WhereUser{
+					{Phone: gptr.Of("13800000000")},
+					{Phone: gptr.Of("13900000000")},
+				},
+			})
+			So(err, ShouldBeNil)
+			So(values, ShouldResemble, []any{"13800000000", "13900000000"})
+		})
+	})
+}
@@ -0,0 +1,242 @@
+package gsql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// operators of subquery
+const (
+	operatorInSubQuery    = "in subquery"
+	operatorNotInSubQuery = "not in subquery"
+	operatorExists        = "exists"
+	operatorNotExists     = "not exists"
+)
+
+var (
+	subQuerierType   = reflect.TypeOf((*subQuerier)(nil)).Elem()
+	softDeleteScopes sync.Map // reflect.Type of PO -> clause.Expression, nil if PO has no soft-delete column
+)
+
+// SubQuery the subquery on the table of PO filtered by Where, which is the type of Where field tagged by
+// `sql_operator:"in subquery"`, `sql_operator:"not in subquery"`, `sql_operator:"exists"` or
+// `sql_operator:"not exists"`.
+//
+// 💡 HINT: tag `sql_select` is the column of PO projected by subquery. As for `exists` and `not exists`,
+// the subquery is correlated by `sql_select` = `sql_field` of the outer table, or uncorrelated without `sql_select`.
+//
+// 💡 HINT: if *Where implements `InjectDefault()`, it is injected on a copy of Where. If PO has a field tagged by
+// `gdal:"soft_delete"`, the soft-deleted records are filtered out unless Unscoped.
+//
+// 💡 HINT: the table of correlated subquery is aliased as `sub_{{table}}`, so that it can be correlated to
+// the outer query on the same table.
+//
+// ⚠️  WARNING: PO must implement `TableName()`.
+//
+// 🚀 example:
+//
+//	type UserWhere struct {
+//		CompanyIn   *gsql.SubQuery[Company, CompanyWhere] `sql_field:"company_id" sql_operator:"in subquery" sql_select:"id"`
+//		HasAccounts *gsql.SubQuery[Account, AccountWhere] `sql_field:"id" sql_operator:"exists" sql_select:"user_id"`
+//	}
+//
+//	where := &UserWhere{
+//		CompanyIn: gsql.NewSubQuery[Company](&CompanyWhere{Active: gptr.Of(true)}),
+//	}
+//
+// SQL: SELECT * FROM `user` WHERE `company_id` IN (SELECT `id` FROM `company` WHERE `active` = true)
+type SubQuery[PO any, Where any] struct {
+	Where    *Where
+	Unscoped bool // include the soft-deleted records of PO
+}
+
+// NewSubQuery new subquery on the table of PO filtered by where
+func NewSubQuery[PO any, Where any](where *Where) *SubQuery[PO, Where] {
+	return &SubQuery[PO, Where]{Where: where}
+}
+
+// subQuerier the table and where condition of subquery
+type subQuerier interface {
+	subQuery() (table string, where clause.Expression, err error)
+	subQueryWhere() any
+}
+
+func (q SubQuery[PO, Where]) subQueryWhere() any {
+	if q.Where == nil {
+		return nil
+	}
+	return q.Where
+}
+
+func (q SubQuery[PO, Where]) subQuery() (string, clause.Expression, error) {
+	tabler, ok := any(new(PO)).(schema.Tabler)
+	if !ok {
+		return "", nil, fmt.Errorf("PO(%s) of subquery must implement TableName()", reflect.TypeOf((*PO)(nil)).Elem())
+	}
+	var exprs []clause.Expression
+	if q.Where != nil {
+		where := *q.Where
+		if injector, ok := any(&where).(interface{ InjectDefault() }); ok {
+			injector.InjectDefault()
+		}
+		expr, err := BuildSQLWhereExpr(&where)
+		if err != nil {
+			return "", nil, err
+		}
+		if !isEmptyExpr(expr) {
+			exprs = append(exprs, expr)
+		}
+	}
+	if scope := softDeleteScopeOf(reflect.TypeOf((*PO)(nil)).Elem()); scope != nil && !q.Unscoped {
+		exprs = append(exprs, scope)
+	}
+	switch len(exprs) {
+	case 0:
+		return tabler.TableName(), nil, nil
+	case 1:
+		return tabler.TableName(), exprs[0], nil
+	}
+	return tabler.TableName(), clause.And(exprs...), nil
+}
+
+// softDeleteScopeOf the predicate of records not soft-deleted, i.e. the column of PO tagged by
+// `gdal:"soft_delete"` is `false` or `0`, nil if PO has no soft-delete column.
+func softDeleteScopeOf(rt reflect.Type) clause.Expression {
+	if scope, ok := softDeleteScopes.Load(rt); ok {
+		if scope == nil {
+			return nil
+		}
+		return scope.(clause.Expression)
+	}
+
+	var scope clause.Expression
+	for i := 0; rt.Kind() == reflect.Struct && i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !hasTagOption(field.Tag.Get("gdal"), "soft_delete") {
+			continue
+		}
+		column := schema.ParseTagSetting(field.Tag.Get("gorm"), ";")["COLUMN"]
+		if column == "" {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		scope = clause.Eq{Column: clause.Column{Name: column}, Value: reflect.Zero(fieldType).Interface()}
+		break
+	}
+	softDeleteScopes.Store(rt, scope)
+	return scope
+}
+
+// hasTagOption whether tag formatted of `opt1;opt2;...` has option.
+func hasTagOption(tag string, option string) bool {
+	for _, opt := range strings.Split(tag, ";") {
+		if strings.TrimSpace(opt) == option {
+			return true
+		}
+	}
+	return false
+}
+
+func isSubQueryOperator(operator string) bool {
+	return operator == operatorInSubQuery || operator == operatorNotInSubQuery ||
+		operator == operatorExists || operator == operatorNotExists
+}
+
+// checkSubQuery the field of subquery operator must be *SubQuery, and `in subquery` must have sql_select.
+func checkSubQuery(field reflect.StructField, sqlOperator, sqlSelect string) error {
+	if !field.Type.Implements(subQuerierType) {
+		return fmt.Errorf("field(%s) operator(%s) must be *gsql.SubQuery", field.Name, sqlOperator)
+	}
+	if sqlSelect == "" && (sqlOperator == operatorInSubQuery || sqlOperator == operatorNotInSubQuery) {
+		return fmt.Errorf("field(%s) operator(%s) need sql_select tag", field.Name, sqlOperator)
+	}
+	return nil
+}
+
+// buildSubQueryExpr build the subquery of data, which is *SubQuery.
+func buildSubQueryExpr(operator string, column string, selectColumn string, data any) (clause.Expression, error) {
+	querier, ok := data.(subQuerier)
+	if !ok {
+		return nil, fmt.Errorf("operator(%s) must be *gsql.SubQuery, but got %T", operator, data)
+	}
+	table, where, err := querier.subQuery()
+	if err != nil {
+		return nil, err
+	}
+	return subQueryExpr{
+		operator:     operator,
+		column:       columnOf(column),
+		table:        table,
+		selectColumn: selectColumn,
+		where:        where,
+	}, nil
+}
+
+// subQueryExpr `column IN (SELECT select FROM table WHERE where)`, or `EXISTS (SELECT 1 FROM table sub_table
+// WHERE sub_table.select = column AND where)` which is correlated to outer table.
+type subQueryExpr struct {
+	operator     string
+	column       clause.Column // column of outer table
+	table        string
+	selectColumn string
+	where        clause.Expression
+}
+
+func (expr subQueryExpr) Build(builder clause.Builder) {
+	var conds []clause.Expression
+	table := clause.Table{Name: expr.table}
+	switch expr.operator {
+	case operatorInSubQuery, operatorNotInSubQuery:
+		builder.WriteQuoted(expr.column)
+		if expr.operator == operatorNotInSubQuery {
+			builder.WriteString(" NOT")
+		}
+		builder.WriteString(" IN (SELECT ")
+		builder.WriteQuoted(clause.Column{Name: expr.selectColumn})
+	default:
+		if expr.operator == operatorNotExists {
+			builder.WriteString("NOT ")
+		}
+		builder.WriteString("EXISTS (SELECT 1")
+		if expr.selectColumn != "" { // correlated
+			outer := expr.column
+			if outer.Table == "" {
+				outer.Table = clause.CurrentTable
+			}
+			table.Alias = subQueryAlias(expr.table) // the outer table may be the same
+			conds = append(conds, clause.Eq{Column: clause.Column{Table: table.Alias, Name: expr.selectColumn}, Value: outer})
+		}
+	}
+	builder.WriteString(" FROM ")
+	builder.WriteQuoted(table)
+	if !isEmptyExpr(expr.where) {
+		conds = append(conds, expr.where)
+	}
+	if len(conds) > 0 {
+		builder.WriteString(" WHERE ")
+		clause.And(conds...).Build(builder)
+	}
+	builder.WriteByte(')')
+}
+
+// subQueryAlias the alias of table in correlated subquery, such as `sub_account` for `db.account`.
+func subQueryAlias(table string) string {
+	return "sub_" + table[strings.LastIndexByte(table, '.')+1:]
+}
+
+// isEmptyExpr whether expr builds nothing.
+func isEmptyExpr(expr clause.Expression) bool {
+	if expr == nil {
+		return true
+	}
+	and, ok := expr.(clause.AndConditions)
+	return ok && len(and.Exprs) == 0
+}
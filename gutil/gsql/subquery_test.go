@@ -0,0 +1,152 @@
+package gsql
+
+import (
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testCompany struct {
+	ID int64 `gorm:"column:id"`
+}
+
+func (testCompany) TableName() string {
+	return "company"
+}
+
+type testCompanyWhere struct {
+	Active    *bool `sql_field:"active"`
+	IsDeleted *bool `sql_field:"is_deleted"`
+}
+
+func (where *testCompanyWhere) InjectDefault() {
+	where.IsDeleted = gptr.Of(false)
+}
+
+type testAccount struct {
+	UserID int64 `gorm:"column:user_id"`
+}
+
+func (testAccount) TableName() string {
+	return "account"
+}
+
+type testAccountWhere struct {
+	Balance *int64 `sql_field:"balance" sql_operator:">"`
+}
+
+type testUser struct {
+	ID        int64  `gorm:"column:id"`
+	ManagerID *int64 `gorm:"column:manager_id"`
+	IsDeleted *bool  `gorm:"column:is_deleted" gdal:"soft_delete"`
+}
+
+func (testUser) TableName() string {
+	return "users"
+}
+
+type testUserWhere struct {
+	Name *string `sql_field:"name"`
+}
+
+func TestSubQuery(t *testing.T) {
+	PatchConvey(t.Name(), t, func() {
+		type WhereUser struct {
+			Name         *string                                  `sql_field:"name"`
+			CompanyIn    *SubQuery[testCompany, testCompanyWhere] `sql_field:"company_id" sql_operator:"in subquery" sql_select:"id"`
+			CompanyNotIn *SubQuery[testCompany, testCompanyWhere] `sql_field:"company_id" sql_operator:"not in subquery" sql_select:"id"`
+			HasAccount   *SubQuery[testAccount, testAccountWhere] `sql_field:"id" sql_operator:"exists" sql_select:"user_id"`
+			NoAccount    *SubQuery[testAccount, testAccountWhere] `sql_field:"id" sql_operator:"not exists" sql_select:"user_id"`
+			AnyAccount   *SubQuery[testAccount, testAccountWhere] `sql_field:"id" sql_operator:"exists"`
+			HasReport    *SubQuery[testUser, testUserWhere]       `sql_field:"id" sql_operator:"exists" sql_select:"manager_id"`
+		}
+
+		PatchConvey("in subquery with InjectDefault", func() {
+			where, err := BuildSQLWhereExpr(&WhereUser{
+				Name:      gptr.Of("dirac"),
+				CompanyIn: NewSubQuery[testCompany](&testCompanyWhere{Active: gptr.Of(true)}),
+			})
+			So(err, ShouldBeNil)
+			query, args := buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE (`name` = ? AND `company_id` IN (SELECT `id` FROM `company` WHERE (`active` = ? AND `is_deleted` = ?)))")
+			So(args, ShouldResemble, []any{"dirac", true, false})
+		})
+
+		PatchConvey("not in subquery without where", func() {
+			where, err := BuildSQLWhereExpr(&WhereUser{
+				CompanyNotIn: &SubQuery[testCompany, testCompanyWhere]{},
+			})
+			So(err, ShouldBeNil)
+			query, args := buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE `company_id` NOT IN (SELECT `id` FROM `company`)")
+			So(args, ShouldBeEmpty)
+		})
+
+		PatchConvey("correlated exists", func() {
+			where, err := BuildSQLWhereExpr(&WhereUser{
+				HasAccount: NewSubQuery[testAccount](&testAccountWhere{Balance: gptr.Of[int64](100)}),
+			})
+			So(err, ShouldBeNil)
+			query, args := buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE EXISTS (SELECT 1 FROM `account` `sub_account` WHERE (`sub_account`.`user_id` = `users`.`id` AND `balance` > ?))")
+			So(args, ShouldResemble, []any{int64(100)})
+
+			where, err = BuildSQLWhereExpr(&WhereUser{
+				NoAccount: NewSubQuery[testAccount](&testAccountWhere{}),
+			})
+			So(err, ShouldBeNil)
+			query, _ = buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE NOT EXISTS (SELECT 1 FROM `account` `sub_account` WHERE `sub_account`.`user_id` = `users`.`id`)")
+		})
+
+		PatchConvey("correlated exists on the same table with soft-delete scope", func() {
+			where, err := BuildSQLWhereExpr(&WhereUser{
+				HasReport: NewSubQuery[testUser](&testUserWhere{Name: gptr.Of("dirac")}),
+			})
+			So(err, ShouldBeNil)
+			query, args := buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE EXISTS (SELECT 1 FROM `users` `sub_users` WHERE (`sub_users`.`manager_id` = `users`.`id` AND (`name` = ? AND `is_deleted` = ?)))")
+			So(args, ShouldResemble, []any{"dirac", false})
+
+			where, err = BuildSQLWhereExpr(&WhereUser{
+				HasReport: &SubQuery[testUser, testUserWhere]{Unscoped: true},
+			})
+			So(err, ShouldBeNil)
+			query, _ = buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE EXISTS (SELECT 1 FROM `users` `sub_users` WHERE `sub_users`.`manager_id` = `users`.`id`)")
+		})
+
+		PatchConvey("uncorrelated exists", func() {
+			where, err := BuildSQLWhereExpr(&WhereUser{
+				AnyAccount: NewSubQuery[testAccount](&testAccountWhere{Balance: gptr.Of[int64](100)}),
+			})
+			So(err, ShouldBeNil)
+			query, _ := buildClauses(where)
+			So(query, ShouldEqual, "SELECT * FROM `users` WHERE EXISTS (SELECT 1 FROM `account` WHERE `balance` > ?)")
+		})
+
+		PatchConvey("invalid subquery fields", func() {
+			_, err := BuildSQLWhereExpr(&struct {
+				CompanyIn *testCompanyWhere `sql_field:"company_id" sql_operator:"in subquery" sql_select:"id"`
+			}{})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "field(CompanyIn) operator(in subquery) must be *gsql.SubQuery")
+
+			_, err = BuildSQLWhereExpr(&struct {
+				CompanyIn *SubQuery[testCompany, testCompanyWhere] `sql_field:"company_id" sql_operator:"in subquery"`
+			}{})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "field(CompanyIn) operator(in subquery) need sql_select tag")
+
+			_, err = BuildSQLWhereExpr(&struct {
+				CompanyIn *SubQuery[testCompanyWhere, testCompanyWhere] `sql_field:"company_id" sql_operator:"in subquery" sql_select:"id"`
+			}{
+				CompanyIn: &SubQuery[testCompanyWhere, testCompanyWhere]{},
+			})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "must implement TableName()")
+		})
+	})
+}
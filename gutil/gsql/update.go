@@ -12,7 +12,9 @@ import (
 //
 // 💡 HINT:
 //
-// ⚠️  WARNING: fields of update must be pointers
+// 💡 HINT: use gsql.Nullable to set a column to NULL, since nil pointers are left unchanged.
+//
+// ⚠️  WARNING: fields of update must be pointers, or gsql.Nullable
 //
 // 🚀 example:
 //
@@ -85,36 +87,142 @@ func fillSQLUpdateFieldMap(rv reflect.Value, st *sqlType) (map[string]any, error
 		if data.Kind() == reflect.Ptr {
 			data = data.Elem()
 		}
+		value := data.Interface()
+		if nullable, ok := value.(nullabler); ok {
+			var set bool
+			if value, set = nullable.nullableValue(); !set {
+				continue
+			}
+			if value == nil { // NULL
+				m[column.Field] = nil
+				continue
+			}
+		}
 		if column.Expr != "" {
-			updater := updaterMap[column.Expr] // must be found, guaranteed by previous operations
-			if updaterResult := updater(column.Field, data.Interface()); updaterResult.SQL != "" {
-				m[column.Field] = updaterResult
+			updater, opts, _ := getUpdater(column.Expr) // must be found, guaranteed by previous operations
+			if updaterResult := updater(column.Field, value); updaterResult != nil {
+				m[column.Field] = opts.dialectChecked(column.Expr, updaterResult)
 			}
 		} else {
-			m[column.Field] = data.Interface()
+			m[column.Field] = value
 		}
 	}
 
 	return m, nil
 }
 
-// SQLUpdater update SQL generator
+// SQLUpdater update SQL generator, the field is not updated if it returns an empty clause.Expr.
+//
+// 💡 HINT: use SQLExprUpdater to generate SQL by dialect.
 type SQLUpdater func(column string, data any) clause.Expr
 
-// updaterMap support `+`, `-` and `merge_json` so far
-var updaterMap = map[string]SQLUpdater{
-	"+": func(column string, data any) clause.Expr {
-		return gorm.Expr(column+" + ?", data)
+// SQLExprUpdater update SQL generator returning any clause.Expression, such as the one generated by dialect,
+// the field is not updated if it returns nil.
+type SQLExprUpdater func(column string, data any) clause.Expression
+
+// updaterMap built-in updaters, and those registered by RegisterUpdater and RegisterExprUpdater
+var updaterMap = map[string]SQLExprUpdater{
+	"+": func(column string, data any) clause.Expression {
+		return gorm.Expr("? + ?", updateColumnOf(column), data)
+	},
+	"-": func(column string, data any) clause.Expression {
+		return gorm.Expr("? - ?", updateColumnOf(column), data)
+	},
+	"*": func(column string, data any) clause.Expression {
+		return gorm.Expr("? * ?", updateColumnOf(column), data)
+	},
+	"/": func(column string, data any) clause.Expression {
+		return gorm.Expr("? / ?", updateColumnOf(column), data)
+	},
+	"greatest": func(column string, data any) clause.Expression { // no less than data
+		col := updateColumnOf(column)
+		return dialectExpr(func(dialect string) clause.Expression {
+			return greatestExpr(col, data, dialect)
+		})
+	},
+	"least": func(column string, data any) clause.Expression { // no greater than data
+		col := updateColumnOf(column)
+		return dialectExpr(func(dialect string) clause.Expression {
+			return leastExpr(col, data, dialect)
+		})
+	},
+	"concat": func(column string, data any) clause.Expression {
+		col := updateColumnOf(column)
+		return dialectExpr(func(dialect string) clause.Expression {
+			return concatExpr(col, data, dialect)
+		})
+	},
+	"now": func(column string, data any) clause.Expression {
+		if now, _ := data.(bool); !now {
+			return nil
+		}
+		return gorm.Expr("CURRENT_TIMESTAMP")
 	},
-	"-": func(column string, data any) clause.Expr {
-		return gorm.Expr(column+" - ?", data)
+	"from_column": func(column string, data any) clause.Expression {
+		from, _ := data.(string)
+		if from == "" {
+			return nil
+		}
+		return gorm.Expr("?", updateColumnOf(from))
+	},
+	"coalesce": func(column string, data any) clause.Expression { // data if NULL
+		return gorm.Expr("COALESCE(?, ?)", updateColumnOf(column), data)
+	},
+	"json_set": func(column string, data any) clause.Expression {
+		return JSONSetDialectExpr(column, data)
+	},
+	"json_remove": func(column string, data any) clause.Expression {
+		return JSONRemoveExpr(column, data)
 	},
-	"json_set": func(column string, data any) clause.Expr {
-		return JSONSetExpr(column, data)
+	"json_array_append": func(column string, data any) clause.Expression {
+		return JSONArrayAppendExpr(column, data)
+	},
+	"json_merge_patch": func(column string, data any) clause.Expression {
+		col := updateColumnOf(column)
+		patch := jsonOf(data)
+		return dialectExpr(func(dialect string) clause.Expression {
+			return jsonMergePatchExpr(col, patch, dialect)
+		})
 	},
 }
 
+// JSONSetExpr set the keys of JSON column by the non-nil fields of data by MySQL `JSON_SET`, whose keys are json
+// tags or field names. It returns an empty clause.Expr if no field of data is set.
+//
+// 💡 HINT: use JSONSetDialectExpr to generate SQL by dialect.
 func JSONSetExpr(column string, data any) clause.Expr {
+	sqlKey, sqlVal := jsonSetArgsOf(data)
+	if len(sqlVal) <= 0 {
+		return clause.Expr{}
+	}
+	var sqlStr string
+	for _, key := range sqlKey {
+		sqlStr += fmt.Sprintf(", '$.%s', ?", key)
+	}
+	return clause.Expr{
+		SQL:  fmt.Sprintf("JSON_SET(`%s` %s)", column, sqlStr),
+		Vars: sqlVal,
+	}
+}
+
+// JSONSetDialectExpr set the keys of JSON column by the non-nil fields of data, whose keys are json tags or
+// field names. It returns nil if no field of data is set.
+//
+// 💡 HINT: the SQL is generated by dialect: `JSON_SET` for MySQL and SQLite, `jsonb_set` for Postgres,
+// and `JSON_MODIFY` for SQL Server.
+func JSONSetDialectExpr(column string, data any) clause.Expression {
+	sqlKey, sqlVal := jsonSetArgsOf(data)
+	if len(sqlVal) <= 0 {
+		return nil
+	}
+	col := updateColumnOf(column)
+	return dialectExpr(func(dialect string) clause.Expression {
+		return jsonSetExpr(col, sqlKey, sqlVal, dialect)
+	})
+}
+
+// jsonSetArgsOf the JSON keys and values of the non-nil fields of data
+func jsonSetArgsOf(data any) ([]string, []any) {
 	reflectValue := reflect.ValueOf(data)
 	reflectType := reflectValue.Type()
 	sqlKey := make([]string, 0)
@@ -142,16 +250,56 @@ func JSONSetExpr(column string, data any) clause.Expr {
 			sqlKey = append(sqlKey, field.Name)
 		}
 	}
+	return sqlKey, sqlVal
+}
 
-	if len(sqlVal) <= 0 {
-		return clause.Expr{}
+// JSONRemoveExpr remove the keys of JSON column, data is a key or a slice of keys, such as `a` or `a.b`.
+// It returns nil if no key is given.
+//
+// 💡 HINT: the SQL is generated by dialect: `JSON_REMOVE` for MySQL and SQLite, `#-` of jsonb for Postgres,
+// and `JSON_MODIFY` to NULL for SQL Server.
+func JSONRemoveExpr(column string, data any) clause.Expression {
+	keys := stringsOf(data)
+	if len(keys) == 0 {
+		return nil
 	}
-	var sqlStr string
-	for _, key := range sqlKey {
-		sqlStr += fmt.Sprintf(", '$.%s', ?", key)
+	col := updateColumnOf(column)
+	return dialectExpr(func(dialect string) clause.Expression {
+		return jsonRemoveExpr(col, keys, dialect)
+	})
+}
+
+// JSONArrayAppendExpr append data to JSON array column, data is a value or a slice of values appended one by one.
+// It returns nil if no value is given.
+//
+// 💡 HINT: the SQL is generated by dialect: `JSON_ARRAY_APPEND` for MySQL, `json_insert` for SQLite,
+// `||` of jsonb for Postgres, and `JSON_MODIFY` with `append` for SQL Server.
+func JSONArrayAppendExpr(column string, data any) clause.Expression {
+	var values []any
+	rv := reflect.ValueOf(data)
+	if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, rv.Index(i).Interface())
+		}
+	} else {
+		values = append(values, data)
 	}
-	return clause.Expr{
-		SQL:  fmt.Sprintf("JSON_SET(`%s` %s)", column, sqlStr),
-		Vars: sqlVal,
+	if len(values) == 0 {
+		return nil
+	}
+	col := updateColumnOf(column)
+	return dialectExpr(func(dialect string) clause.Expression {
+		return jsonArrayAppendExpr(col, values, dialect)
+	})
+}
+
+// stringsOf data which is string or slice of string
+func stringsOf(data any) []string {
+	switch v := data.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
 	}
+	return nil
 }
@@ -6,6 +6,7 @@ import (
 	. "github.com/bytedance/mockey"
 	"github.com/dirac-lee/gdal/gutil/gptr"
 	. "github.com/smartystreets/goconvey/convey"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
@@ -65,7 +66,9 @@ func TestBuildSQLUpdate(t *testing.T) {
 				}, func(m map[string]any, err error) {
 					So(err, ShouldBeNil)
 					So(m, ShouldHaveLength, 1)
-					So(m["age"], ShouldResemble, clause.Expr{SQL: "age + ?", Vars: []any{1}})
+					sql, vars := buildExpr(DialectMySQL, m["age"].(clause.Expression))
+					So(sql, ShouldEqual, "`users`.`age` + ?")
+					So(vars, ShouldResemble, []any{1})
 				})
 			})
 
@@ -77,7 +80,9 @@ func TestBuildSQLUpdate(t *testing.T) {
 				}, func(m map[string]any, err error) {
 					So(err, ShouldBeNil)
 					So(m, ShouldHaveLength, 1)
-					So(m["age"], ShouldResemble, clause.Expr{SQL: "age - ?", Vars: []any{1}})
+					sql, vars := buildExpr(DialectMySQL, m["age"].(clause.Expression))
+					So(sql, ShouldEqual, "`users`.`age` - ?")
+					So(vars, ShouldResemble, []any{1})
 				})
 			})
 
@@ -98,10 +103,9 @@ func TestBuildSQLUpdate(t *testing.T) {
 					}, func(m map[string]any, err error) {
 						So(err, ShouldBeNil)
 						So(m, ShouldHaveLength, 1)
-						So(m["data"], ShouldResemble, clause.Expr{
-							SQL:  "JSON_SET(`data` , '$.a', ?, '$.c', ?)",
-							Vars: []any{"a", false},
-						})
+						sql, vars := buildExpr(DialectMySQL, m["data"].(clause.Expression))
+						So(sql, ShouldEqual, "JSON_SET(`users`.`data`, '$.a', ?, '$.c', ?)")
+						So(vars, ShouldResemble, []any{"a", false})
 					})
 				})
 
@@ -156,3 +160,145 @@ func TestBuildSQLUpdate(t *testing.T) {
 		})
 	})
 }
+
+func TestBuiltinUpdaters(t *testing.T) {
+	PatchConvey(t.Name(), t, func() {
+		type UpdateUser struct {
+			ScoreMul      *float64 `sql_field:"score" sql_expr:"*"`
+			ScoreDiv      *int     `sql_field:"score" sql_expr:"/"`
+			AgeAtLeast    *int     `sql_field:"age" sql_expr:"greatest"`
+			AgeAtMost     *int     `sql_field:"age" sql_expr:"least"`
+			NameAppend    *string  `sql_field:"name" sql_expr:"concat"`
+			UpdateTimeNow *bool    `sql_field:"update_time" sql_expr:"now"`
+			NickFromName  *string  `sql_field:"nickname" sql_expr:"from_column"`
+			NickOrDefault *string  `sql_field:"nickname" sql_expr:"coalesce"`
+			MetaRemove    []string `sql_field:"meta" sql_expr:"json_remove"`
+			TagsAppend    []string `sql_field:"tags" sql_expr:"json_array_append"`
+			MetaPatch     *string  `sql_field:"meta" sql_expr:"json_merge_patch"`
+		}
+		build := func(dialect string, update *UpdateUser, column string) (string, []any) {
+			m, err := BuildSQLUpdate(update)
+			So(err, ShouldBeNil)
+			So(m, ShouldHaveLength, 1)
+			return buildExpr(dialect, m[column].(clause.Expression))
+		}
+
+		PatchConvey("arithmetic", func() {
+			sql, vars := build(DialectMySQL, &UpdateUser{ScoreMul: gptr.Of(1.5)}, "score")
+			So(sql, ShouldEqual, "`users`.`score` * ?")
+			So(vars, ShouldResemble, []any{1.5})
+
+			sql, _ = build(DialectMySQL, &UpdateUser{ScoreDiv: gptr.Of(2)}, "score")
+			So(sql, ShouldEqual, "`users`.`score` / ?")
+		})
+
+		PatchConvey("greatest and least", func() {
+			update := &UpdateUser{AgeAtLeast: gptr.Of(18)}
+			sql, vars := build(DialectMySQL, update, "age")
+			So(sql, ShouldEqual, "GREATEST(`users`.`age`, ?)")
+			So(vars, ShouldResemble, []any{18})
+			sql, _ = build(DialectSQLite, update, "age")
+			So(sql, ShouldEqual, "MAX(`users`.`age`, ?)")
+			sql, vars = build(DialectSQLServer, update, "age")
+			So(sql, ShouldEqual, "CASE WHEN `users`.`age` < ? THEN ? ELSE `users`.`age` END")
+			So(vars, ShouldResemble, []any{18, 18})
+
+			update = &UpdateUser{AgeAtMost: gptr.Of(60)}
+			sql, _ = build(DialectPostgres, update, "age")
+			So(sql, ShouldEqual, "LEAST(`users`.`age`, ?)")
+			sql, _ = build(DialectSQLite, update, "age")
+			So(sql, ShouldEqual, "MIN(`users`.`age`, ?)")
+		})
+
+		PatchConvey("concat", func() {
+			update := &UpdateUser{NameAppend: gptr.Of("-x")}
+			sql, _ := build(DialectMySQL, update, "name")
+			So(sql, ShouldEqual, "CONCAT(`users`.`name`, ?)")
+			sql, _ = build(DialectPostgres, update, "name")
+			So(sql, ShouldEqual, "`users`.`name` || ?")
+		})
+
+		PatchConvey("now", func() {
+			sql, vars := build(DialectMySQL, &UpdateUser{UpdateTimeNow: gptr.Of(true)}, "update_time")
+			So(sql, ShouldEqual, "CURRENT_TIMESTAMP")
+			So(vars, ShouldBeEmpty)
+
+			m, err := BuildSQLUpdate(&UpdateUser{UpdateTimeNow: gptr.Of(false)})
+			So(err, ShouldBeNil)
+			So(m, ShouldBeEmpty)
+		})
+
+		PatchConvey("from_column and coalesce", func() {
+			sql, _ := build(DialectMySQL, &UpdateUser{NickFromName: gptr.Of("name")}, "nickname")
+			So(sql, ShouldEqual, "`users`.`name`")
+
+			sql, vars := build(DialectMySQL, &UpdateUser{NickOrDefault: gptr.Of("anonymous")}, "nickname")
+			So(sql, ShouldEqual, "COALESCE(`users`.`nickname`, ?)")
+			So(vars, ShouldResemble, []any{"anonymous"})
+		})
+
+		PatchConvey("json_remove", func() {
+			update := &UpdateUser{MetaRemove: []string{"a", "b.c"}}
+			sql, vars := build(DialectMySQL, update, "meta")
+			So(sql, ShouldEqual, "JSON_REMOVE(`users`.`meta`, ?, ?)")
+			So(vars, ShouldResemble, []any{"$.a", "$.b.c"})
+			sql, vars = build(DialectPostgres, update, "meta")
+			So(sql, ShouldEqual, "CAST(`users`.`meta` AS jsonb) #- CAST(? AS text[]) #- CAST(? AS text[])")
+			So(vars, ShouldResemble, []any{"{a}", "{b,c}"})
+			sql, vars = build(DialectSQLServer, update, "meta")
+			So(sql, ShouldEqual, "JSON_MODIFY(JSON_MODIFY(`users`.`meta`, ?, NULL), ?, NULL)")
+			So(vars, ShouldResemble, []any{"$.a", "$.b.c"})
+		})
+
+		PatchConvey("json_array_append", func() {
+			update := &UpdateUser{TagsAppend: []string{"go", "sql"}}
+			sql, vars := build(DialectMySQL, update, "tags")
+			So(sql, ShouldEqual, "JSON_ARRAY_APPEND(`users`.`tags`, '$', ?, '$', ?)")
+			So(vars, ShouldResemble, []any{"go", "sql"})
+			sql, _ = build(DialectSQLite, update, "tags")
+			So(sql, ShouldEqual, "json_insert(`users`.`tags`, '$[#]', ?, '$[#]', ?)")
+			sql, vars = build(DialectPostgres, update, "tags")
+			So(sql, ShouldEqual, "CAST(`users`.`tags` AS jsonb) || CAST(? AS jsonb)")
+			So(vars, ShouldResemble, []any{`["go","sql"]`})
+			sql, _ = build(DialectSQLServer, update, "tags")
+			So(sql, ShouldEqual, "JSON_MODIFY(JSON_MODIFY(`users`.`tags`, 'append $', ?), 'append $', ?)")
+		})
+
+		PatchConvey("json_merge_patch", func() {
+			update := &UpdateUser{MetaPatch: gptr.Of(`{"a":1}`)}
+			sql, vars := build(DialectMySQL, update, "meta")
+			So(sql, ShouldEqual, "JSON_MERGE_PATCH(`users`.`meta`, ?)")
+			So(vars, ShouldResemble, []any{`{"a":1}`})
+			sql, _ = build(DialectSQLite, update, "meta")
+			So(sql, ShouldEqual, "json_patch(`users`.`meta`, ?)")
+
+			m, err := BuildSQLUpdate(update)
+			So(err, ShouldBeNil)
+			db, _ := gorm.Open(namedDialector{name: DialectSQLServer}, nil)
+			stmt := &gorm.Statement{DB: db, Table: "users", Clauses: map[string]clause.Clause{}}
+			m["meta"].(clause.Expression).Build(stmt)
+			So(db.Error, ShouldNotBeNil)
+			So(db.Error.Error(), ShouldEqual, "expr(json_merge_patch) unsupported by dialect(sqlserver)")
+		})
+
+		PatchConvey("invalid kinds", func() {
+			_, err := BuildSQLUpdate(&struct {
+				Name *string `sql_field:"name" sql_expr:"*"`
+			}{})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "field(Name) expr(*) does not accept string")
+
+			_, err = BuildSQLUpdate(&struct {
+				Age []int `sql_field:"age" sql_expr:"+"`
+			}{})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "field(Age) expr(+) does not accept slice")
+
+			_, err = BuildSQLUpdate(&struct {
+				Age Nullable[string] `sql_field:"age" sql_expr:"/"`
+			}{})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "field(Age) expr(/) does not accept string")
+		})
+	})
+}
@@ -73,41 +73,81 @@ func buildSQLWhereV2(rv reflect.Value, rt reflect.Type) (clause.Expression, erro
 		exprs = append(exprs, clause.And(firstExprs...))
 	}
 
-	orClauseList := getOrClauseList(rv, rt)
-	if len(orClauseList) == 0 {
+	groupExprs, err := buildSQLGroupExprsV2(rv, rt)
+	if err != nil {
+		return nil, err
+	}
+	if len(groupExprs) == 0 {
 		return clause.And(firstExprs...), nil
 	}
-	for _, orClause := range orClauseList { // multiple fields with tag $or
-		if !orClause.IsValid() {
-			continue
-		}
-		orExprs, err := buildSQLOrExprsV2(orClause)
+	exprs = append(exprs, groupExprs...) // use AND to combine fields with group tag
+	return clause.And(exprs...), nil
+}
+
+// buildSQLGroupExprsV2 build the fields with tag $or, $and or $not, empty groups are dropped.
+func buildSQLGroupExprsV2(rv reflect.Value, rt reflect.Type) ([]clause.Expression, error) {
+	var exprs []clause.Expression
+	for _, group := range getGroupList(rv, rt) {
+		expr, err := buildSQLGroupExprV2(group.expr, group.value)
 		if err != nil {
 			return nil, err
 		}
-		if len(orExprs) == 0 {
-			continue
-		}
-		if len(orExprs) == 1 {
-			exprs = append(exprs, orExprs[0])  // when just one expr, no need OR
-		} else {
-			orClauseExpr := clause.Or(orExprs...) // use OR to combine elem of slice field with tag $or
-			exprs = append(exprs, orClauseExpr)   // use AND to combine fields with tag $or
+		if expr != nil {
+			exprs = append(exprs, expr)
 		}
 	}
-	if len(exprs) == 0 {
+	return exprs, nil
+}
+
+// buildSQLGroupExprV2 build the group field, which is a struct (or pointer to struct) or a slice of them.
+//
+// 💡 HINT: fields of struct are combined by the group, such as `a OR b` of $or, `NOT (a AND b)` of $not, while
+// elements of slice are combined by the group, such as `(a1 AND b1) OR (a2 AND b2)` of $or, and `NOT (elem1 OR elem2)`
+// of $not, i.e. none of them.
+func buildSQLGroupExprV2(group string, rv reflect.Value) (clause.Expression, error) {
+	if !rv.IsValid() {
 		return nil, nil
 	}
-	return clause.And(exprs...), nil
-}
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
 
-func buildSQLOrExprsV2(rv reflect.Value) ([]clause.Expression, error) {
-	if rv.Kind() != reflect.Array && rv.Kind() != reflect.Slice { // 每个 $or 都必须是 slice
-		return nil, errors.New("or clauses must be slice or array")
+	switch rv.Kind() {
+	case reflect.Struct:
+		if group != groupOr {
+			expr, err := buildSQLWhereV2(rv, rv.Type())
+			if err != nil || expr == nil {
+				return nil, err
+			}
+			return combineGroupExprs(group, []clause.Expression{expr}), nil
+		}
+		exprs, err := buildSQLAndExprsV2(rv, rv.Type())
+		if err != nil {
+			return nil, err
+		}
+		groupExprs, err := buildSQLGroupExprsV2(rv, rv.Type())
+		if err != nil {
+			return nil, err
+		}
+		return combineGroupExprs(group, append(exprs, groupExprs...)), nil
+	case reflect.Slice, reflect.Array:
+		exprs, err := buildSQLElemExprsV2(rv)
+		if err != nil {
+			return nil, err
+		}
+		return combineGroupExprs(group, exprs), nil
+	default:
+		return nil, fmt.Errorf("%s clauses must be struct, slice or array", group)
 	}
+}
 
+// buildSQLElemExprsV2 build every element of slice as Where struct
+func buildSQLElemExprsV2(rv reflect.Value) ([]clause.Expression, error) {
 	var exprs []clause.Expression
-	for i := 0; i < rv.Len(); i++ { // range slice field with tag $or
+	for i := 0; i < rv.Len(); i++ { // range slice field with group tag
 		erv := rv.Index(i)
 		if !erv.IsValid() {
 			continue
@@ -132,6 +172,51 @@ func buildSQLOrExprsV2(rv reflect.Value) ([]clause.Expression, error) {
 	return exprs, nil
 }
 
+// combineGroupExprs combine exprs by group, nil if exprs is empty
+func combineGroupExprs(group string, exprs []clause.Expression) clause.Expression {
+	if len(exprs) == 0 {
+		return nil
+	}
+	if len(exprs) == 1 && group != groupNot {
+		return exprs[0] // when just one expr, no need OR
+	}
+	switch group {
+	case groupOr:
+		return clause.Or(exprs...)
+	case groupNot:
+		if len(exprs) == 1 {
+			return notExpr{expr: exprs[0]}
+		}
+		return notExpr{expr: clause.Or(exprs...)}
+	default:
+		return clause.And(exprs...)
+	}
+}
+
+// notExpr `NOT (expr)`, unlike clause.Not, expr is negated as a whole.
+type notExpr struct {
+	expr clause.Expression
+}
+
+func (not notExpr) Build(builder clause.Builder) {
+	builder.WriteString("NOT ")
+	switch expr := not.expr.(type) {
+	case clause.AndConditions:
+		if len(expr.Exprs) > 1 { // parenthesised already
+			expr.Build(builder)
+			return
+		}
+	case clause.OrConditions:
+		if len(expr.Exprs) > 1 { // parenthesised already
+			expr.Build(builder)
+			return
+		}
+	}
+	builder.WriteByte('(')
+	not.expr.Build(builder)
+	builder.WriteByte(')')
+}
+
 func buildSQLAndExprsV2(rv reflect.Value, rt reflect.Type) ([]clause.Expression, error) {
 	var exprs []clause.Expression
 
@@ -153,105 +238,125 @@ func buildSQLAndExprsV2(rv reflect.Value, rt reflect.Type) ([]clause.Expression,
 			field = field.Elem()
 		}
 		data := field.Interface()
+		if nullable, ok := data.(nullabler); ok { // NULL is `IS NULL` of operator `=`
+			var set bool
+			if data, set = nullable.nullableValue(); !set {
+				continue
+			}
+		}
 
-		builder, err := GetWhereExpr(column.Operator)
-		if err != nil {
-			return nil, err
+		if isSubQueryOperator(column.Operator) {
+			expr, err := buildSubQueryExpr(column.Operator, column.Field, column.Select, data)
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, expr)
+			continue
+		}
+		builder, opts, ok := getWhereOperator(column.Operator)
+		if !ok {
+			return nil, fmt.Errorf("unsupported operator %s", column.Operator)
 		}
-		sqlField := strings.Replace(column.Field, ".", "`.`", 1)
-		expr, err := builder(sqlField, data)
+		expr, err := builder(column.Field, data)
 		if err != nil {
 			return nil, err
 		}
-		exprs = append(exprs, expr)
+		if expr == nil { // no condition, such as an unbounded range
+			continue
+		}
+		exprs = append(exprs, opts.dialectChecked(column.Operator, expr))
 	}
 
 	return exprs, nil
 }
 
+// GetWhereExpr get the builder of built-in or registered operator
 func GetWhereExpr(operator string) (SQLWhereExprBuilder, error) {
-	expr, ok := whereMap[operator]
+	expr, _, ok := getWhereOperator(operator)
 	if !ok {
 		return nil, fmt.Errorf("unsupported operator %s", operator)
 	}
 	return expr, nil
 }
 
-// SQLWhereExprBuilder where SQL generator
+// SQLWhereExprBuilder where SQL generator, the field is ignored if the expression is nil
 type SQLWhereExprBuilder func(column string, data any) (clause.Expression, error)
 
+// whereMap built-in operators, and those registered by RegisterWhereOperator
 var whereMap = map[string]SQLWhereExprBuilder{
 	"<": func(column string, data any) (clause.Expression, error) {
-		return clause.Lt{Column: column, Value: data}, nil
+		return clause.Lt{Column: columnOf(column), Value: data}, nil
 	},
 	"<=": func(column string, data any) (clause.Expression, error) {
-		return clause.Lte{Column: column, Value: data}, nil
+		return clause.Lte{Column: columnOf(column), Value: data}, nil
 	},
 	"=": func(column string, data any) (clause.Expression, error) {
-		return clause.Eq{Column: column, Value: data}, nil
+		return clause.Eq{Column: columnOf(column), Value: data}, nil
 	},
 	"": func(column string, data any) (clause.Expression, error) {
-		return clause.Eq{Column: column, Value: data}, nil
+		return clause.Eq{Column: columnOf(column), Value: data}, nil
 	},
 	"!=": func(column string, data any) (clause.Expression, error) {
-		return clause.Neq{Column: column, Value: data}, nil
+		return clause.Neq{Column: columnOf(column), Value: data}, nil
 	},
 	"<>": func(column string, data any) (clause.Expression, error) {
-		return clause.Neq{Column: column, Value: data}, nil
+		return clause.Neq{Column: columnOf(column), Value: data}, nil
 	},
 	">": func(column string, data any) (clause.Expression, error) {
-		return clause.Gt{Column: column, Value: data}, nil
+		return clause.Gt{Column: columnOf(column), Value: data}, nil
 	},
 	">=": func(column string, data any) (clause.Expression, error) {
-		return clause.Gte{Column: column, Value: data}, nil
+		return clause.Gte{Column: columnOf(column), Value: data}, nil
 	},
 	"null": func(column string, data any) (clause.Expression, error) {
 		v, isBool := data.(bool)
 		if !isBool {
 			return clause.Expr{}, errors.New("field with tag `null` must be bool")
 		}
-		var not string
 		if !v {
-			not = "NOT "
+			return gorm.Expr("? IS NOT NULL", columnOf(column)), nil
 		}
-		expr := fmt.Sprintf("`%v` IS %vNULL", column, not)
-		return gorm.Expr(expr), nil
+		return gorm.Expr("? IS NULL", columnOf(column)), nil
 	},
 	"in": func(column string, data any) (clause.Expression, error) {
-		expr := fmt.Sprintf("`%v` IN (?)", column)
-		return gorm.Expr(expr, data), nil
+		return gorm.Expr("? IN (?)", columnOf(column), data), nil
 	},
 	"not in": func(column string, data any) (clause.Expression, error) {
-		expr := fmt.Sprintf("`%v` NOT IN (?)", column)
-		return gorm.Expr(expr, data), nil
+		return gorm.Expr("? NOT IN (?)", columnOf(column), data), nil
 	},
 	"full like": func(column string, data any) (clause.Expression, error) {
 		v, isStr := data.(string)
 		if !isStr {
 			return clause.Expr{}, errors.New("field with tag `full like` must be string")
 		}
-		return clause.Like{Column: column, Value: "%" + v + "%"}, nil
+		return clause.Like{Column: columnOf(column), Value: "%" + v + "%"}, nil
 	},
 	"left like": func(column string, data any) (clause.Expression, error) {
 		v, isStr := data.(string)
 		if !isStr {
 			return clause.Expr{}, errors.New("field with tag `left like` must be string")
 		}
-		return clause.Like{Column: column, Value: "%" + v}, nil
+		return clause.Like{Column: columnOf(column), Value: "%" + v}, nil
 	},
 	"right like": func(column string, data any) (clause.Expression, error) {
 		v, isStr := data.(string)
 		if !isStr {
 			return clause.Expr{}, errors.New("field with tag `right like` must be string")
 		}
-		return clause.Like{Column: column, Value: v + "%"}, nil
+		return clause.Like{Column: columnOf(column), Value: v + "%"}, nil
 	},
 	"like": func(column string, data any) (clause.Expression, error) {
 		v, isStr := data.(string)
 		if !isStr {
 			return clause.Expr{}, errors.New("field with tag `like` must be string")
 		}
-		return clause.Like{Column: column, Value: v}, nil
+		return clause.Like{Column: columnOf(column), Value: v}, nil
+	},
+	"between": func(column string, data any) (clause.Expression, error) {
+		return buildRangeExpr("between", column, data, false)
+	},
+	"not between": func(column string, data any) (clause.Expression, error) {
+		return buildRangeExpr("not between", column, data, true)
 	},
 	"json_contains": func(column string, data any) (clause.Expression, error) {
 		return JSONContains(column, data), nil
@@ -269,9 +374,15 @@ var whereMap = map[string]SQLWhereExprBuilder{
 	},
 }
 
+// JSONContains whether JSON column contains data, which is JSON text or value to be marshaled.
+//
+// 💡 HINT: the SQL is generated by dialect: `JSON_CONTAINS` for MySQL, `@>` of jsonb for Postgres,
+// `json_each` for SQLite, and `OPENJSON` for SQL Server.
 func JSONContains(column string, data any) clause.Expression {
-	expr := fmt.Sprintf("JSON_CONTAINS(%s, ?)", column)
-	return gorm.Expr(expr, data)
+	col := columnOf(column)
+	return dialectExpr(func(dialect string) clause.Expression {
+		return jsonContainsExpr(col, data, dialect)
+	})
 }
 
 func JSONContainsExprs(column string, data any) ([]clause.Expression, error) {
@@ -289,29 +400,48 @@ func JSONContainsExprs(column string, data any) ([]clause.Expression, error) {
 	return exprs, nil
 }
 
-var orCache sync.Map
+// group expressions of tag sql_expr, combining sub-Where structs
+const (
+	groupOr  = "$or"
+	groupAnd = "$and"
+	groupNot = "$not"
+)
+
+func isGroupExpr(expr string) bool {
+	return expr == groupOr || expr == groupAnd || expr == groupNot
+}
+
+type groupField struct {
+	expr  string // $or, $and or $not
+	value reflect.Value
+}
+
+type groupIndex struct {
+	expr  string
+	index int
+}
+
+var groupCache sync.Map
 
-func getOrClauseList(rv reflect.Value, rt reflect.Type) (orClauses []reflect.Value) {
-	var orIndices []int
-	value, cached := orCache.Load(rt)
+func getGroupList(rv reflect.Value, rt reflect.Type) (groups []groupField) {
+	var groupIndices []groupIndex
+	value, cached := groupCache.Load(rt)
 	if cached {
-		orIndices = value.([]int)
-		for _, index := range orIndices {
-			fieldValue := rv.Field(index)
-			orClauses = append(orClauses, fieldValue)
+		groupIndices = value.([]groupIndex)
+		for _, group := range groupIndices {
+			groups = append(groups, groupField{expr: group.expr, value: rv.Field(group.index)})
 		}
-		return orClauses
+		return groups
 	}
 
 	for i := 0; i < rt.NumField(); i++ {
-		fieldValue := rv.Field(i)
 		structField := rt.Field(i)
 		sqlExpr := strings.TrimSpace(structField.Tag.Get("sql_expr"))
-		if sqlExpr == "$or" {
-			orIndices = append(orIndices, i)
-			orClauses = append(orClauses, fieldValue)
+		if isGroupExpr(sqlExpr) {
+			groupIndices = append(groupIndices, groupIndex{expr: sqlExpr, index: i})
+			groups = append(groups, groupField{expr: sqlExpr, value: rv.Field(i)})
 		}
 	}
-	orCache.Store(rt, orIndices)
-	return orClauses
+	groupCache.Store(rt, groupIndices)
+	return groups
 }
@@ -614,6 +614,148 @@ func TestBuildSQLWhere(t *testing.T) {
 			})
 		})
 
+		PatchConvey("group expression", func() {
+			type UserGroup struct {
+				UserName *string `sql_field:"user_name"`
+				UserAge  *int64  `sql_field:"user_age"`
+			}
+			type WhereUser struct {
+				UserID   *int64      `sql_field:"user_id"`
+				UserName *string     `sql_field:"user_name"`
+				UserAge  *int64      `sql_field:"user_age"`
+				AnyOf    *UserGroup  `sql_expr:"$or"`
+				AllOf    *UserGroup  `sql_expr:"$and"`
+				Not      *UserGroup  `sql_expr:"$not"`
+				NoneOf   []WhereUser `sql_expr:"$not"`
+				AllOfAll []WhereUser `sql_expr:"$and"`
+				Or       []WhereUser `sql_expr:"$or"`
+			}
+
+			PatchConvey("$not of struct", func() {
+				testBuildSQLWhere(WhereUser{
+					UserID: gptr.Of[int64](1),
+					Not: &UserGroup{
+						UserName: gptr.Of("dirac"),
+						UserAge:  gptr.Of[int64](18),
+					},
+				}, func(where clause.Expression, err error) {
+					So(err, ShouldBeNil)
+					query, args := buildClauses(where)
+					So(query, ShouldEqual, "SELECT * FROM `users` WHERE (`user_id` = ? AND NOT (`user_name` = ? AND `user_age` = ?))")
+					So(args, ShouldResemble, []any{int64(1), "dirac", int64(18)})
+				})
+			})
+
+			PatchConvey("$not of single field", func() {
+				testBuildSQLWhere(WhereUser{
+					Not: &UserGroup{UserName: gptr.Of("dirac")},
+				}, func(where clause.Expression, err error) {
+					So(err, ShouldBeNil)
+					query, args := buildClauses(where)
+					So(query, ShouldEqual, "SELECT * FROM `users` WHERE NOT (`user_name` = ?)")
+					So(args, ShouldResemble, []any{"dirac"})
+				})
+			})
+
+			PatchConvey("$not of slice is none of elements", func() {
+				testBuildSQLWhere(WhereUser{
+					NoneOf: []WhereUser{
+						{UserName: gptr.Of("dirac")},
+						{UserAge: gptr.Of[int64](18)},
+					},
+				}, func(where clause.Expression, err error) {
+					So(err, ShouldBeNil)
+					query, args := buildClauses(where)
+					So(query, ShouldEqual, "SELECT * FROM `users` WHERE NOT (`user_name` = ? OR `user_age` = ?)")
+					So(args, ShouldResemble, []any{"dirac", int64(18)})
+				})
+			})
+
+			PatchConvey("$or of struct combines fields by OR", func() {
+				testBuildSQLWhere(WhereUser{
+					UserID: gptr.Of[int64](1),
+					AnyOf: &UserGroup{
+						UserName: gptr.Of("dirac"),
+						UserAge:  gptr.Of[int64](18),
+					},
+				}, func(where clause.Expression, err error) {
+					So(err, ShouldBeNil)
+					query, args := buildClauses(where)
+					So(query, ShouldEqual, "SELECT * FROM `users` WHERE (`user_id` = ? AND (`user_name` = ? OR `user_age` = ?))")
+					So(args, ShouldResemble, []any{int64(1), "dirac", int64(18)})
+				})
+			})
+
+			PatchConvey("$and of struct and slice", func() {
+				testBuildSQLWhere(WhereUser{
+					AllOf: &UserGroup{
+						UserName: gptr.Of("dirac"),
+						UserAge:  gptr.Of[int64](18),
+					},
+					AllOfAll: []WhereUser{
+						{UserID: gptr.Of[int64](1)},
+						{UserID: gptr.Of[int64](2)},
+					},
+				}, func(where clause.Expression, err error) {
+					So(err, ShouldBeNil)
+					query, args := buildClauses(where)
+					So(query, ShouldEqual, "SELECT * FROM `users` WHERE ((`user_name` = ? AND `user_age` = ?) AND (`user_id` = ? AND `user_id` = ?))")
+					So(args, ShouldResemble, []any{"dirac", int64(18), int64(1), int64(2)})
+				})
+			})
+
+			PatchConvey("nested groups", func() {
+				testBuildSQLWhere(WhereUser{
+					UserID: gptr.Of[int64](1),
+					Or: []WhereUser{
+						{
+							UserName: gptr.Of("dirac"),
+							Or: []WhereUser{
+								{UserAge: gptr.Of[int64](18)},
+								{UserAge: gptr.Of[int64](20)},
+							},
+						},
+						{
+							Not: &UserGroup{UserName: gptr.Of("lee")},
+						},
+					},
+				}, func(where clause.Expression, err error) {
+					So(err, ShouldBeNil)
+					query, args := buildClauses(where)
+					So(query, ShouldEqual, "SELECT * FROM `users` WHERE (`user_id` = ? AND ((`user_name` = ? AND (`user_age` = ? OR `user_age` = ?)) OR NOT (`user_name` = ?)))")
+					So(args, ShouldResemble, []any{int64(1), "dirac", int64(18), int64(20), "lee"})
+				})
+			})
+
+			PatchConvey("empty groups are dropped", func() {
+				testBuildSQLWhere(WhereUser{
+					UserID:   gptr.Of[int64](1),
+					AnyOf:    &UserGroup{},
+					Not:      &UserGroup{},
+					NoneOf:   []WhereUser{{}},
+					AllOfAll: []WhereUser{},
+					Or:       []WhereUser{{AnyOf: &UserGroup{}}},
+				}, func(where clause.Expression, err error) {
+					So(err, ShouldBeNil)
+					query, args := buildClauses(where)
+					So(query, ShouldEqual, "SELECT * FROM `users` WHERE `user_id` = ?")
+					So(args, ShouldResemble, []any{int64(1)})
+				})
+			})
+
+			PatchConvey("group must be struct or slice", func() {
+				testBuildSQLWhere(struct {
+					UserID *int64 `sql_field:"user_id"`
+					Not    *int64 `sql_expr:"$not"`
+				}{
+					Not: gptr.Of[int64](1),
+				}, func(where clause.Expression, err error) {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "$not clauses must be struct, slice or array")
+				})
+			})
+		})
+
 		PatchConvey("json_contains expression", func() {
 			type UserWhere struct {
 				ID                   *int     `sql_field:"id" sql_operator:"="`
@@ -636,7 +778,7 @@ func TestBuildSQLWhere(t *testing.T) {
 				}, func(where clause.Expression, err error) {
 					So(err, ShouldBeNil)
 					query, args := buildClauses(where)
-					So(query, ShouldEqual, "SELECT * FROM `users` WHERE (`id` = ? AND JSON_CONTAINS(friend_ids, ?))")
+					So(query, ShouldEqual, "SELECT * FROM `users` WHERE (`id` = ? AND JSON_CONTAINS(`friend_ids`, ?))")
 					So(args, ShouldResemble, []any{110, "110"})
 				})
 			})
@@ -647,7 +789,7 @@ func TestBuildSQLWhere(t *testing.T) {
 				}, func(where clause.Expression, err error) {
 					So(err, ShouldBeNil)
 					query, args := buildClauses(where)
-					So(query, ShouldEqual, "SELECT * FROM `users` WHERE (`id` = ? AND (JSON_CONTAINS(friend_ids, ?) OR JSON_CONTAINS(friend_ids, ?) OR JSON_CONTAINS(friend_ids, ?)))")
+					So(query, ShouldEqual, "SELECT * FROM `users` WHERE (`id` = ? AND (JSON_CONTAINS(`friend_ids`, ?) OR JSON_CONTAINS(`friend_ids`, ?) OR JSON_CONTAINS(`friend_ids`, ?)))")
 					So(args, ShouldResemble, []any{110, "110", "111", "112"})
 				})
 			})
@@ -658,7 +800,7 @@ func TestBuildSQLWhere(t *testing.T) {
 				}, func(where clause.Expression, err error) {
 					So(err, ShouldBeNil)
 					query, args := buildClauses(where)
-					So(query, ShouldEqual, "SELECT * FROM `users` WHERE (`id` = ? AND JSON_CONTAINS(friend_ids, ?))")
+					So(query, ShouldEqual, "SELECT * FROM `users` WHERE (`id` = ? AND JSON_CONTAINS(`friend_ids`, ?))")
 					So(args, ShouldResemble, []any{110, "110"})
 				})
 			})
@@ -669,7 +811,7 @@ func TestBuildSQLWhere(t *testing.T) {
 				}, func(where clause.Expression, err error) {
 					So(err, ShouldBeNil)
 					query, args := buildClauses(where)
-					So(query, ShouldEqual, "SELECT * FROM `users` WHERE (`id` = ? AND (JSON_CONTAINS(friend_ids, ?) AND JSON_CONTAINS(friend_ids, ?) AND JSON_CONTAINS(friend_ids, ?)))")
+					So(query, ShouldEqual, "SELECT * FROM `users` WHERE (`id` = ? AND (JSON_CONTAINS(`friend_ids`, ?) AND JSON_CONTAINS(`friend_ids`, ?) AND JSON_CONTAINS(`friend_ids`, ?)))")
 					So(args, ShouldResemble, []any{110, "110", "111", "112"})
 				})
 			})
@@ -0,0 +1,134 @@
+package gdal
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	globalInterceptorsMu sync.RWMutex
+	globalInterceptors   []Interceptor
+)
+
+// OpKind the kind of DAL operation
+type OpKind string
+
+const (
+	OpCreate OpKind = "create"
+	OpSave   OpKind = "save"
+	OpDelete OpKind = "delete"
+	OpUpdate OpKind = "update"
+	OpFind   OpKind = "find"
+	OpFirst  OpKind = "first"
+	OpCount  OpKind = "count"
+	OpExist  OpKind = "exist"
+
+	OpAggregate OpKind = "aggregate"
+	OpPluck     OpKind = "pluck"
+)
+
+// Operation the descriptor of DAL operation passed through interceptors.
+//
+// 💡 HINT: interceptors can modify Where, Update and Config before calling next, such as
+// restricting the records by authorization.
+//
+// ⚠️  WARNING: PO, Where and Update are what the caller passed, with sensitive values unredacted, so do not
+// log them as they are. Only the SQL logged and the error returned by next are redacted.
+type Operation struct {
+	Kind   OpKind
+	Table  string       // table name by TableName() of PO
+	PO     any          // PO, or pointer to PO(s) which receives the records
+	Where  any          // Where struct, or clause.Expression
+	Update any          // Update struct, or map from column to value
+	Config *QueryConfig // resolved query options
+	Result any          // pointer which receives the aggregates by Aggregate, or the column values by Pluck
+
+	// SoftDelete whether Delete marks the records deleted by the columns in Update rather than removes them,
+	// which is the case for the PO with a field tagged by `gdal:"soft_delete"`.
+	SoftDelete bool
+
+	// RowsAffected rows affected by Create, Save, Update and Delete, rows found by Find and First,
+	// the count by Count, 1 (found) or 0 (not found) by Exist, and the rows scanned by Aggregate and Pluck.
+	// It is set after next returns.
+	RowsAffected int64
+
+	sensitive []any             // values of sensitive columns, redacted from SQL logs and errors
+	whereExpr clause.Expression // where expression built by DAL, nil if not built
+	sqlErr    error             // error of SQL reported to the gorm logger, which logs it already
+}
+
+// Invoker executes the operation.
+type Invoker func(ctx context.Context, op *Operation) error
+
+// Interceptor intercepts every DAL operation, it must call next to continue the operation.
+//
+// 🚀 example:
+//
+//	type SlowLogInterceptor struct{}
+//
+//	func (SlowLogInterceptor) Intercept(ctx context.Context, op *gdal.Operation, next gdal.Invoker) error {
+//		start := time.Now()
+//		err := next(ctx, op)
+//		if cost := time.Since(start); cost > time.Second {
+//			log.Printf("slow %s on %s: %v", op.Kind, op.Table, cost)
+//		}
+//		return err
+//	}
+type Interceptor interface {
+	Intercept(ctx context.Context, op *Operation, next Invoker) error
+}
+
+// InterceptorFunc function as Interceptor
+type InterceptorFunc func(ctx context.Context, op *Operation, next Invoker) error
+
+func (f InterceptorFunc) Intercept(ctx context.Context, op *Operation, next Invoker) error {
+	return f(ctx, op, next)
+}
+
+// RegisterInterceptor register interceptors for all DALs globally, which are called before those of DAL.
+//
+// ⚠️  WARNING: not supposed to be called after DALs are in use.
+func RegisterInterceptor(interceptors ...Interceptor) {
+	globalInterceptorsMu.Lock()
+	defer globalInterceptorsMu.Unlock()
+	globalInterceptors = append(globalInterceptors, interceptors...)
+}
+
+// chainInterceptors chain interceptors around invoker, the first interceptor is the outermost.
+func chainInterceptors(interceptors []Interceptor, invoker Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, op *Operation) error {
+			return interceptor.Intercept(ctx, op, next)
+		}
+	}
+	return invoker
+}
+
+// tableOf the table name of po, which can be PO, pointer to PO, or slice of them.
+func tableOf(po any) string {
+	rt := structTypeOf(po)
+	if rt == nil {
+		return ""
+	}
+	if tabler, ok := reflect.New(rt).Interface().(schema.Tabler); ok {
+		return tabler.TableName()
+	}
+	return ""
+}
+
+// structTypeOf the struct type of po, which can be PO, pointer to PO, or slice of them.
+func structTypeOf(po any) reflect.Type {
+	rt := reflect.TypeOf(po)
+	for rt != nil && (rt.Kind() == reflect.Pointer || rt.Kind() == reflect.Slice || rt.Kind() == reflect.Array) {
+		rt = rt.Elem()
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil
+	}
+	return rt
+}
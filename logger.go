@@ -0,0 +1,247 @@
+package gdal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	logConfigMu sync.RWMutex
+	logConfig   = defaultLogConfig()
+)
+
+// LogLevel the level of operation log
+type LogLevel int
+
+const (
+	LogSilent LogLevel = iota + 1 // log nothing
+	LogError                      // log failed operations
+	LogWarn                       // log failed and slow operations
+	LogInfo                       // log all operations
+)
+
+func (level LogLevel) String() string {
+	switch level {
+	case LogSilent:
+		return "SILENT"
+	case LogError:
+		return "ERROR"
+	case LogWarn:
+		return "WARN"
+	case LogInfo:
+		return "INFO"
+	default:
+		return fmt.Sprintf("LogLevel(%d)", int(level))
+	}
+}
+
+// LogEntry the fields of operation log
+type LogEntry struct {
+	Table        string
+	Op           OpKind
+	RowsAffected int64
+	Latency      time.Duration
+	Slow         bool   // latency exceeds the slow threshold
+	Shape        string // where condition with values replaced by `?`, such as "`name` = ? AND `id` IN (?)"
+	Fingerprint  string // hash of Shape, operations with the same fingerprint differ only in values
+	Err          error
+}
+
+// Logger logs the operations
+type Logger interface {
+	Log(ctx context.Context, level LogLevel, entry *LogEntry)
+}
+
+// LoggerFunc function as Logger
+type LoggerFunc func(ctx context.Context, level LogLevel, entry *LogEntry)
+
+func (f LoggerFunc) Log(ctx context.Context, level LogLevel, entry *LogEntry) {
+	f(ctx, level, entry)
+}
+
+// LogConfig the configuration of operation log
+type LogConfig struct {
+	Logger        Logger        // logger, the standard log by default
+	Level         LogLevel      // operations above level are not logged, LogWarn by default
+	SlowThreshold time.Duration // operations slower than threshold are slow, 200ms by default, negative to disable
+}
+
+func defaultLogConfig() LogConfig {
+	return LogConfig{
+		Logger:        LoggerFunc(stdLog),
+		Level:         LogWarn,
+		SlowThreshold: 200 * time.Millisecond,
+	}
+}
+
+// SetLogConfig set the configuration of operation log globally, zero fields are set to default.
+//
+// 🚀 example:
+//
+//	gdal.SetLogConfig(gdal.LogConfig{
+//		Logger:        gdal.LoggerFunc(func(ctx context.Context, level gdal.LogLevel, entry *gdal.LogEntry) {
+//			// adapt to your logger
+//		}),
+//		Level:         gdal.LogInfo,
+//		SlowThreshold: time.Second,
+//	})
+func SetLogConfig(config LogConfig) {
+	defaults := defaultLogConfig()
+	if config.Logger == nil {
+		config.Logger = defaults.Logger
+	}
+	if config.Level == 0 {
+		config.Level = defaults.Level
+	}
+	if config.SlowThreshold == 0 {
+		config.SlowThreshold = defaults.SlowThreshold
+	}
+	logConfigMu.Lock()
+	defer logConfigMu.Unlock()
+	logConfig = config
+}
+
+func getLogConfig() LogConfig {
+	logConfigMu.RLock()
+	defer logConfigMu.RUnlock()
+	return logConfig
+}
+
+// logInterceptor log the operation by the global LogConfig
+//
+// 💡 HINT: the errors of SQL are logged by the gorm logger already, so that they are not logged at LogError
+// again, but at LogInfo or LogWarn (slow) along with the other operations.
+var logInterceptor = InterceptorFunc(func(ctx context.Context, op *Operation, next Invoker) error {
+	start := time.Now()
+	err := next(ctx, op)
+	latency := time.Since(start)
+
+	config := getLogConfig()
+	slow := config.SlowThreshold > 0 && latency > config.SlowThreshold
+	level := LogInfo
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && op.sqlErr == nil: // SQL errors are logged by gorm
+		level = LogError
+	case slow:
+		level = LogWarn
+	}
+	if level > config.Level && !op.Config.debug {
+		return err
+	}
+
+	entry := &LogEntry{
+		Table:        op.Table,
+		Op:           op.Kind,
+		RowsAffected: op.RowsAffected,
+		Latency:      latency,
+		Slow:         slow,
+		Err:          err,
+	}
+	entry.Shape, entry.Fingerprint = whereShape(op)
+	config.Logger.Log(ctx, level, entry)
+	return err
+})
+
+func stdLog(ctx context.Context, level LogLevel, entry *LogEntry) {
+	log.Printf("[GDAL] %s table=%s op=%s rows=%d latency=%v slow=%t fingerprint=%s shape=%q err=%v",
+		level, entry.Table, entry.Op, entry.RowsAffected, entry.Latency, entry.Slow, entry.Fingerprint, entry.Shape, entry.Err)
+}
+
+var placeholdersRegexp = regexp.MustCompile(`\?(,\s*\?)+`)
+
+// whereShape the where condition built for op with values replaced by `?`, and its fingerprint.
+func whereShape(op *Operation) (string, string) {
+	if op.whereExpr == nil {
+		return "", ""
+	}
+	builder := &shapeBuilder{}
+	op.whereExpr.Build(builder)
+	shape := placeholdersRegexp.ReplaceAllString(builder.String(), "?") // `IN (?,?,?)` -> `IN (?)`
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(shape))
+	return shape, fmt.Sprintf("%016x", hash.Sum64())
+}
+
+// traceLogger gorm logger tracing the SQL error of op, which is logged by the wrapped logger.
+type traceLogger struct {
+	logger.Interface
+	op *Operation
+}
+
+func (l *traceLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &traceLogger{Interface: l.Interface.LogMode(level), op: l.op}
+}
+
+func (l *traceLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if err != nil {
+		l.op.sqlErr = err
+	}
+	l.Interface.Trace(ctx, begin, fc, err)
+}
+
+// ParamsFilter called by gorm before explaining SQL with params for logging.
+func (l *traceLogger) ParamsFilter(ctx context.Context, sql string, params ...any) (string, []any) {
+	if filter, ok := l.Interface.(gorm.ParamsFilter); ok {
+		return filter.ParamsFilter(ctx, sql, params...)
+	}
+	return sql, params
+}
+
+// shapeBuilder clause.Builder writing `?` in spite of values
+type shapeBuilder struct {
+	strings.Builder
+}
+
+func (builder *shapeBuilder) WriteQuoted(field any) {
+	switch v := field.(type) {
+	case clause.Column:
+		if len(v.Table) > 0 {
+			builder.WriteString("`" + v.Table + "`.")
+		}
+		if v.Raw {
+			builder.WriteString(v.Name)
+		} else {
+			builder.WriteString("`" + v.Name + "`")
+		}
+	case clause.Table:
+		builder.WriteString("`" + v.Name + "`")
+	default:
+		builder.WriteString(fmt.Sprint("`", v, "`"))
+	}
+}
+
+func (builder *shapeBuilder) AddVar(writer clause.Writer, vars ...any) {
+	for i, v := range vars {
+		if i > 0 {
+			writer.WriteByte(',')
+		}
+		switch v := v.(type) {
+		case clause.Column, clause.Table:
+			builder.WriteQuoted(v)
+		case clause.Expression:
+			v.Build(builder)
+		default:
+			if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+				writer.WriteString("(?)")
+			} else {
+				writer.WriteByte('?')
+			}
+		}
+	}
+}
+
+func (builder *shapeBuilder) AddError(err error) error {
+	return err
+}
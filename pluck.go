@@ -0,0 +1,71 @@
+package gdal
+
+import (
+	"context"
+
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"gorm.io/gorm/schema"
+)
+
+// Pluck query the values of a single column of records by condition, without scanning into PO.
+//
+// 💡 HINT: T is the type of column values, such as int64. Use pointer type such as *int64 if the column is
+// nullable.
+//
+// ⚠️  WARNING: column must be mapped by PO with tag `gorm:"column:{{column_name}}"`.
+//
+// 🚀 example:
+//
+//	companyIDs, err := gdal.Pluck[model.User, model.UserWhere, model.UserUpdate, int64](ctx, userDAL, "company_id", where)
+//
+// SQL:
+// SELECT `company_id` FROM `user` WHERE `active` = true and `is_deleted` = false
+func Pluck[PO schema.Tabler, Where any, Update any, T any](ctx context.Context, gdal *GDAL[PO, Where, Update], column string, where *Where, options ...QueryOption) ([]T, error) {
+	return pluck[PO, Where, Update, T](ctx, gdal, column, where, options)
+}
+
+// Distinct query the distinct values of a single column of records by condition.
+//
+// 💡 HINT: ref Pluck.
+//
+// ⚠️  WARNING: WithOrder should order by column only, which some databases require with DISTINCT.
+//
+// 🚀 example:
+//
+//	companyIDs, err := gdal.Distinct[model.User, model.UserWhere, model.UserUpdate, int64](ctx, userDAL, "company_id", where)
+//
+// SQL:
+// SELECT DISTINCT `company_id` FROM `user` WHERE `active` = true and `is_deleted` = false
+func Distinct[PO schema.Tabler, Where any, Update any, T any](ctx context.Context, gdal *GDAL[PO, Where, Update], column string, where *Where, options ...QueryOption) ([]T, error) {
+	return pluck[PO, Where, Update, T](ctx, gdal, column, where, append(options[:len(options):len(options)], withDistinct()))
+}
+
+func pluck[PO schema.Tabler, Where any, Update any, T any](ctx context.Context, gdal *GDAL[PO, Where, Update], column string, where *Where, options []QueryOption) ([]T, error) {
+	selector, err := GetSelectorFromPOs(new(PO)) // the columns mapped by PO gorm tag
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, selected := range selector {
+		found = found || selected == column
+	}
+	if !found {
+		return nil, gerror.ColumnNotFoundErr(structTypeOf(new(PO)), column)
+	}
+	options, err = gdal.scopeIfSoftDelete(options) // filter out soft-deleted records unless unscoped.
+	if err != nil {
+		return nil, err
+	}
+	injectDefaultIfHas(where)                      // when field is not set in `where`,  insert customized default value  if customer has set it.
+	indexedDAL := gdal.forceIndexIfHas(ctx, where) // force index if  it is set in `where`.
+
+	var ptrs []*T // scan via *T, so that NULL is scanned into nil if T is pointer
+	if err = indexedDAL.extended().Pluck(ctx, gdal.MakePO(), where, column, &ptrs, options...); err != nil {
+		return nil, err
+	}
+	values := make([]T, 0, len(ptrs))
+	for _, ptr := range ptrs {
+		values = append(values, *ptr)
+	}
+	return values, nil
+}
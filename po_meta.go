@@ -0,0 +1,129 @@
+package gdal
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"github.com/dirac-lee/gdal/gutil/greflect"
+)
+
+var (
+	type2POMeta sync.Map // struct type -> *poMeta
+)
+
+// poMeta the column metadata of PO, read from gorm tag and gdal tag.
+type poMeta struct {
+	structType   reflect.Type
+	columns      []string       // columns in the order of fields
+	column2Index map[string]int // column -> field index
+
+	primaryKeys []string     // columns of primary key, tagged by gorm `primaryKey`, or `id` by default
+	keyType     reflect.Type // type of primary key, generated struct if composite
+
+	softDelete string // column tagged by `gdal:"soft_delete"`
+	version    string // column tagged by `gdal:"version"`
+
+	sensitive []string // columns tagged by `gdal:"sensitive"`
+}
+
+// getPOMeta read the column metadata of PO
+func getPOMeta[PO any]() (*poMeta, error) {
+	structType, err := greflect.GetElemStructType(reflect.TypeOf((*PO)(nil)))
+	if err != nil {
+		return nil, err
+	}
+	return getStructMeta(structType)
+}
+
+// getStructMeta read the column metadata of struct type
+func getStructMeta(structType reflect.Type) (*poMeta, error) {
+	value, ok := type2POMeta.Load(structType)
+	if ok {
+		return value.(*poMeta), nil
+	}
+	return getPOMetaSlow(structType)
+}
+
+// getPOMetaSlow read gorm tag from structType, then build poMeta
+func getPOMetaSlow(structType reflect.Type) (*poMeta, error) {
+	meta := &poMeta{
+		structType:   structType,
+		column2Index: make(map[string]int),
+	}
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		gormTag := strings.TrimSpace(structField.Tag.Get("gorm"))
+		tagKVs, err := getKVsFromTag(gormTag)
+		if err != nil {
+			return nil, err
+		}
+		columnName := tagKVs["column"]
+		if columnName == "" {
+			continue
+		}
+		meta.columns = append(meta.columns, columnName)
+		meta.column2Index[columnName] = i
+		if isPrimaryKeyTag(tagKVs) {
+			meta.primaryKeys = append(meta.primaryKeys, columnName)
+		}
+
+		for _, option := range getOptionsFromTag(structField.Tag.Get("gdal")) {
+			switch option {
+			case "soft_delete":
+				if err := checkSoftDeleteField(structField); err != nil {
+					return nil, err
+				}
+				meta.softDelete = columnName
+			case "version":
+				if err := checkVersionField(structField); err != nil {
+					return nil, err
+				}
+				meta.version = columnName
+			case "sensitive":
+				meta.sensitive = append(meta.sensitive, columnName)
+			default:
+				return nil, gerror.GDALTagInvalidErr(structField.Name, option)
+			}
+		}
+	}
+	if err := meta.buildKeyType(); err != nil {
+		return nil, err
+	}
+	type2POMeta.Store(structType, meta)
+	return meta, nil
+}
+
+// getOptionsFromTag translate tag formatted of `opt1;opt2;...` to option list
+func getOptionsFromTag(tag string) []string {
+	var options []string
+	for _, option := range strings.Split(tag, ";") {
+		option = strings.TrimSpace(option)
+		if len(option) > 0 {
+			options = append(options, option)
+		}
+	}
+	return options
+}
+
+// hasColumn whether column is mapped by PO
+func (meta *poMeta) hasColumn(column string) bool {
+	_, ok := meta.column2Index[column]
+	return ok
+}
+
+// fieldType the type of field mapping to column
+//
+// ⚠️  WARNING: column must be mapped by PO.
+func (meta *poMeta) fieldType(column string) reflect.Type {
+	return meta.structType.Field(meta.column2Index[column]).Type
+}
+
+// valueOf the value of field mapping to column in po
+//
+// ⚠️  WARNING: column must be mapped by PO.
+func (meta *poMeta) valueOf(po any, column string) any {
+	rv := reflect.Indirect(reflect.ValueOf(po))
+	return rv.Field(meta.column2Index[column]).Interface()
+}
@@ -0,0 +1,242 @@
+package gdal
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"gorm.io/gorm/clause"
+)
+
+// isPrimaryKeyTag whether gorm tag declares primary key, such as `primaryKey` or `primary_key`.
+func isPrimaryKeyTag(tagKVs map[string]string) bool {
+	for k := range tagKVs {
+		if k = strings.ToUpper(k); k == "PRIMARYKEY" || k == "PRIMARY_KEY" {
+			return true
+		}
+	}
+	return false
+}
+
+// buildKeyType determine the primary key, then build its type.
+//
+// 💡 HINT: column `id` is taken as primary key if no field tagged by gorm `primaryKey`. As for composite
+// primary key, a key struct is generated, whose fields are the same as the primary key fields of PO.
+func (meta *poMeta) buildKeyType() error {
+	if len(meta.primaryKeys) == 0 && meta.hasColumn("id") {
+		meta.primaryKeys = []string{"id"}
+	}
+	switch len(meta.primaryKeys) {
+	case 0:
+		return nil
+	case 1:
+		meta.keyType = meta.fieldType(meta.primaryKeys[0])
+		return nil
+	}
+
+	fields := make([]reflect.StructField, 0, len(meta.primaryKeys))
+	for _, column := range meta.primaryKeys {
+		structField := meta.structType.Field(meta.column2Index[column])
+		if !structField.IsExported() {
+			return gerror.GDALErrorf("field (%s) of composite primary key must be exported", structField.Name)
+		}
+		fields = append(fields, reflect.StructField{
+			Name: structField.Name,
+			Type: structField.Type,
+			Tag:  reflect.StructTag(`gorm:"column:` + column + `"`),
+		})
+	}
+	meta.keyType = reflect.StructOf(fields)
+	return nil
+}
+
+// keyOf the primary key of po, the generated key struct if composite.
+func (meta *poMeta) keyOf(po any) any {
+	if len(meta.primaryKeys) == 1 {
+		return meta.valueOf(po, meta.primaryKeys[0])
+	}
+	key := reflect.New(meta.keyType).Elem()
+	for i, column := range meta.primaryKeys {
+		key.Field(i).Set(reflect.ValueOf(meta.valueOf(po, column)))
+	}
+	return key.Interface()
+}
+
+// hasZeroKey whether any field of primary key of po is zero value, which means po has not been created yet.
+func (meta *poMeta) hasZeroKey(po any) bool {
+	for _, column := range meta.primaryKeys {
+		if reflect.ValueOf(meta.valueOf(po, column)).IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// keyValues the values of primary key columns in key.
+//
+// 💡 HINT: key can be the value of single primary key, or any struct (pointer) whose fields are mapped to
+// the primary key columns by gorm tag, such as the generated key struct and PO itself.
+func (meta *poMeta) keyValues(key any) ([]any, error) {
+	if len(meta.primaryKeys) == 0 {
+		return nil, gerror.GDALErrorf("model (%v) has no primary key", meta.structType)
+	}
+	rv := reflect.Indirect(reflect.ValueOf(key))
+	if !rv.IsValid() {
+		return nil, gerror.InvalidReflectValueErr(rv)
+	}
+	if len(meta.primaryKeys) == 1 && (rv.Type() == meta.keyType || rv.Kind() != reflect.Struct) {
+		if !isKeyCompatible(rv.Type(), meta.keyType) {
+			return nil, gerror.GDALErrorf("primary key of model (%v) must be %v, but got %v", meta.structType, meta.keyType, rv.Type())
+		}
+		return []any{rv.Interface()}, nil
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, gerror.GDALErrorf("composite primary key of model (%v) must be struct, but got %v", meta.structType, rv.Type())
+	}
+
+	keyMeta, err := getStructMeta(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+	values := make([]any, 0, len(meta.primaryKeys))
+	for _, column := range meta.primaryKeys {
+		if !keyMeta.hasColumn(column) {
+			return nil, gerror.ColumnNotFoundErr(rv.Type(), column)
+		}
+		values = append(values, rv.Field(keyMeta.column2Index[column]).Interface())
+	}
+	return values, nil
+}
+
+// isKeyCompatible whether the value of type rt can be taken as the single primary key of type keyType, i.e. the
+// same type, or both integers, or both strings.
+//
+// 💡 HINT: it is stricter than reflect.Type.ConvertibleTo, which converts integer to string as rune.
+func isKeyCompatible(rt reflect.Type, keyType reflect.Type) bool {
+	for keyType.Kind() == reflect.Pointer {
+		keyType = keyType.Elem()
+	}
+	if rt == keyType {
+		return true
+	}
+	switch {
+	case isIntegerKind(rt.Kind()) && isIntegerKind(keyType.Kind()):
+		return true
+	case rt.Kind() == reflect.String && keyType.Kind() == reflect.String:
+		return true
+	}
+	return false
+}
+
+func isIntegerKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// keyExpr the predicate of records whose primary key is one of keys.
+//
+// SQL:
+// `id` = ?, `id` IN (?,?) or (`a` = ? AND `b` = ?) OR (`a` = ? AND `b` = ?) if composite.
+func (meta *poMeta) keyExpr(keys ...any) (clause.Expression, error) {
+	if len(meta.primaryKeys) == 0 {
+		return nil, gerror.GDALErrorf("model (%v) has no primary key", meta.structType)
+	}
+	if len(keys) == 0 { // IN (NULL) matches nothing
+		return clause.IN{Column: clause.Column{Name: meta.primaryKeys[0]}}, nil
+	}
+
+	if len(meta.primaryKeys) == 1 {
+		column := clause.Column{Name: meta.primaryKeys[0]}
+		values := make([]any, 0, len(keys))
+		for _, key := range keys {
+			keyValues, err := meta.keyValues(key)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, keyValues[0])
+		}
+		if len(values) == 1 {
+			return clause.Eq{Column: column, Value: values[0]}, nil
+		}
+		return clause.IN{Column: column, Values: values}, nil
+	}
+
+	orExprs := make([]clause.Expression, 0, len(keys))
+	for _, key := range keys {
+		keyValues, err := meta.keyValues(key)
+		if err != nil {
+			return nil, err
+		}
+		andExprs := make([]clause.Expression, 0, len(keyValues))
+		for i, column := range meta.primaryKeys {
+			andExprs = append(andExprs, clause.Eq{Column: clause.Column{Name: column}, Value: keyValues[i]})
+		}
+		orExprs = append(orExprs, clause.And(andExprs...))
+	}
+	if len(orExprs) == 1 {
+		return orExprs[0], nil
+	}
+	return clause.Or(orExprs...), nil
+}
+
+// keysOf translate slice of primary keys to []any
+func keysOf(ids any) ([]any, error) {
+	rv := reflect.Indirect(reflect.ValueOf(ids))
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, gerror.GDALErrorf("primary keys must be slice or array, but got %v", reflect.TypeOf(ids))
+	}
+	keys := make([]any, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		keys = append(keys, rv.Index(i).Interface())
+	}
+	return keys, nil
+}
+
+// pkWhere condition by primary key
+type pkWhere struct {
+	clause.Expression
+}
+
+func (where pkWhere) ForceIndex() string {
+	return "PRIMARY"
+}
+
+// pkWhere build the condition of records whose primary key is one of keys.
+func (gdal *GDAL[PO, Where, Update]) pkWhere(keys ...any) (pkWhere, error) {
+	meta, err := getPOMeta[PO]()
+	if err != nil {
+		return pkWhere{}, err
+	}
+	expr, err := meta.keyExpr(keys...)
+	if err != nil {
+		return pkWhere{}, err
+	}
+	return pkWhere{Expression: expr}, nil
+}
+
+// KeyOf get the primary key of po.
+//
+// 💡 HINT: if PO has composite primary key, the key is a generated struct whose fields are the primary key
+// fields of PO, otherwise, it is the value of the primary key field.
+//
+// 🚀 example:
+//
+//	key, err := userRoleDAL.KeyOf(&model.UserRole{UserID: 110, RoleID: 3})
+//	po, err := userRoleDAL.QueryByID(ctx, key)
+//
+// SQL:
+// SELECT `user_id`,`role_id`,`create_time` FROM `user_role` WHERE (`user_id` = 110 AND `role_id` = 3) ORDER BY `user_role`.`user_id` LIMIT 1
+func (gdal *GDAL[PO, Where, Update]) KeyOf(po *PO) (any, error) {
+	meta, err := getPOMeta[PO]()
+	if err != nil {
+		return nil, err
+	}
+	if len(meta.primaryKeys) == 0 {
+		return nil, gerror.GDALErrorf("model (%v) has no primary key", meta.structType)
+	}
+	return meta.keyOf(po), nil
+}
@@ -0,0 +1,109 @@
+package gdal
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	projectionChecked sync.Map // [2]reflect.Type{T, PO} -> error of checkProjection
+)
+
+// FindAs query by condition, and project the records into T.
+//
+// 💡 HINT: T is a read model whose fields are tagged by `gorm:"column:{{column_name}}"`, and only the columns
+// of T are selected. T needs no TableName, since the table is that of PO. All QueryOptions are supported.
+//
+// ⚠️  WARNING: every field of T must be mapped to a column of PO, which is checked at the first use of T.
+//
+// 🚀 example:
+//
+//	type UserBrief struct {
+//		ID   int64  `gorm:"column:id"`
+//		Name string `gorm:"column:name"`
+//	}
+//
+//	briefs, err := gdal.FindAs[UserBrief](ctx, userDAL, where, gdal.WithLimit(10), gdal.WithOrder("birthday"))
+//
+// SQL:
+// SELECT `id`,`name` FROM `user` WHERE `active` = true and `is_deleted` = false ORDER BY birthday LIMIT 10
+func FindAs[T any, PO schema.Tabler, Where any, Update any](ctx context.Context, gdal *GDAL[PO, Where, Update], where *Where, options ...QueryOption) ([]*T, error) {
+	projectionDAL, err := projectionOf[T](gdal)
+	if err != nil {
+		return nil, err
+	}
+	var ts []*T
+	err = projectionDAL.Find(ctx, &ts, where, options...)
+	return ts, err
+}
+
+// FirstAs query the first record by condition, and project it into T, nil if not found.
+//
+// 💡 HINT: ref FindAs and QueryFirst.
+//
+// 🚀 example:
+//
+//	brief, err := gdal.FirstAs[UserBrief](ctx, userDAL, where, gdal.WithOrder("birthday"))
+//
+// SQL:
+// SELECT `id`,`name` FROM `user` WHERE `active` = true and `is_deleted` = false ORDER BY birthday,`user`.`id` LIMIT 1
+func FirstAs[T any, PO schema.Tabler, Where any, Update any](ctx context.Context, gdal *GDAL[PO, Where, Update], where *Where, options ...QueryOption) (*T, error) {
+	projectionDAL, err := projectionOf[T](gdal)
+	if err != nil {
+		return nil, err
+	}
+	var t T
+	err = projectionDAL.First(ctx, &t, where, options...)
+	if err != nil {
+		if gerror.IsErrRecordNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// projectionOf check the projection T of PO, then generate a new GDAL whose model is PO, so that the
+// records are queried from the table of PO into T.
+func projectionOf[T any, PO schema.Tabler, Where any, Update any](gdal *GDAL[PO, Where, Update]) (*GDAL[PO, Where, Update], error) {
+	if err := checkProjection[T, PO](); err != nil {
+		return nil, err
+	}
+	return gdal.withDB(gdal.DB().Model(new(PO))), nil
+}
+
+// checkProjection whether the columns of T by selector are all mapped by PO, which is cached by types.
+func checkProjection[T any, PO schema.Tabler]() error {
+	key := [2]reflect.Type{reflect.TypeOf((*T)(nil)).Elem(), reflect.TypeOf((*PO)(nil)).Elem()}
+	if checked, ok := projectionChecked.Load(key); ok {
+		if checked == nil {
+			return nil
+		}
+		return checked.(error)
+	}
+
+	err := checkProjectionSlow[T, PO]()
+	projectionChecked.Store(key, err)
+	return err
+}
+
+func checkProjectionSlow[T any, PO schema.Tabler]() error {
+	selector, err := GetSelectorFromPOs(new(T)) // cached by type2Selector
+	if err != nil {
+		return err
+	}
+	meta, err := getPOMeta[PO]()
+	if err != nil {
+		return err
+	}
+	for _, column := range selector {
+		if !meta.hasColumn(column) {
+			return gerror.GDALErrorf("column (%s) of projection (%v) not found in model (%v)", column, reflect.TypeOf((*T)(nil)).Elem(), meta.structType)
+		}
+	}
+	return nil
+}
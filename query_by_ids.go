@@ -0,0 +1,248 @@
+package gdal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"gorm.io/gorm/schema"
+)
+
+// defaultChunkSize the default max number of primary keys in a statement of MQueryByIDs
+const defaultChunkSize = 500
+
+// MQueryMapByIDs query by primary keys, and return the map from primary key to record.
+//
+// 💡 HINT: the same as MQueryByIDs, except that ids is typed so that the records can be indexed by it. The
+// missing primary keys are absent from the map, or reported by WithStrict.
+//
+// ⚠️  WARNING: the selected columns, if any, must include the primary key.
+//
+// 🚀 example:
+//
+//	userMap, err := gdal.MQueryMapByIDs(ctx, userDAL, gslice.Of[int64](123, 456, 789))
+//
+// SQL:
+// SELECT `id`,`name`,`age`,`birthday`,`company_id`,`manager_id`,`active`,`create_time`,`update_time`,`is_deleted`
+// FROM `user` WHERE `id` IN (123,456,789)
+func MQueryMapByIDs[ID comparable, PO schema.Tabler, Where any, Update any](ctx context.Context, gdal *GDAL[PO, Where, Update], ids []ID, options ...QueryOption) (map[ID]*PO, error) {
+	meta, err := getPOMeta[PO]()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]any, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, id)
+	}
+	pos, err := gdal.mQueryByIDs(ctx, meta, keys, options)
+	if err != nil && !gerror.IsMissingIDsErr(err) {
+		return nil, err
+	}
+
+	poMap := make(map[string]*PO, len(pos))
+	for _, po := range pos {
+		poMap[canonicalKey(meta.poKeyValues(po))] = po
+	}
+	result := make(map[ID]*PO, len(pos))
+	for _, id := range ids {
+		keyValues, keyErr := meta.keyValues(id)
+		if keyErr != nil {
+			return nil, keyErr
+		}
+		if po, ok := poMap[canonicalKey(keyValues)]; ok {
+			result[id] = po
+		}
+	}
+	return result, err
+}
+
+// mQueryByIDs query records by distinct keys in chunks, in the order of keys.
+//
+// 💡 HINT: with WithOrder, WithLimit or WithOffset, the records are queried by a single statement in the
+// order given, and missing keys are not reported if limited.
+func (gdal *GDAL[PO, Where, Update]) mQueryByIDs(ctx context.Context, meta *poMeta, keys []any, options []QueryOption) ([]*PO, error) {
+	keys, canonicals, err := distinctKeys(meta, keys)
+	if err != nil || len(keys) == 0 { // no statement for empty keys
+		return nil, err
+	}
+
+	config := MakeQueryConfig(options)
+	if config.Order != nil || config.Limit != nil || config.Offset != nil {
+		where, err := gdal.pkWhere(keys...)
+		if err != nil {
+			return nil, err
+		}
+		var pos []*PO
+		if err = gdal.Find(ctx, &pos, where, options...); err != nil {
+			return nil, err
+		}
+		if !config.strict || config.Limit != nil || config.Offset != nil {
+			return pos, nil
+		}
+		return pos, missingKeys(meta, keys, canonicals, pos)
+	}
+
+	chunks := chunksOf(keys, config.chunkSize)
+	results := make([][]*PO, len(chunks))
+	parallel := config.parallel
+	if _, inTx := scopeFromCtx(ctx); inTx || parallel < 1 {
+		parallel = 1
+	}
+	if parallel == 1 || len(chunks) == 1 {
+		for i, chunk := range chunks {
+			if results[i], err = gdal.queryChunk(ctx, chunk, options); err != nil {
+				return nil, err
+			}
+		}
+	} else if err = gdal.queryChunksParallel(ctx, chunks, results, parallel, options); err != nil {
+		return nil, err
+	}
+
+	poMap := make(map[string]*PO, len(keys))
+	for _, chunk := range results {
+		for _, po := range chunk {
+			poMap[canonicalKey(meta.poKeyValues(po))] = po
+		}
+	}
+	pos := make([]*PO, 0, len(poMap))
+	var missing []any
+	for i, canonical := range canonicals {
+		if po, ok := poMap[canonical]; ok {
+			pos = append(pos, po)
+		} else {
+			missing = append(missing, keys[i])
+		}
+	}
+	if config.strict && len(missing) > 0 {
+		return pos, gerror.MissingIDsErr(meta.structType, missing)
+	}
+	return pos, nil
+}
+
+// queryChunk query the records of a chunk of keys.
+func (gdal *GDAL[PO, Where, Update]) queryChunk(ctx context.Context, keys []any, options []QueryOption) ([]*PO, error) {
+	where, err := gdal.pkWhere(keys...)
+	if err != nil {
+		return nil, err
+	}
+	var pos []*PO
+	err = gdal.Find(ctx, &pos, where, options...)
+	return pos, err
+}
+
+// queryChunksParallel query at most parallel chunks concurrently, and return the first error.
+func (gdal *GDAL[PO, Where, Update]) queryChunksParallel(ctx context.Context, chunks [][]any, results [][]*PO, parallel int, options []QueryOption) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, parallel)
+	for i := range chunks {
+		sem <- struct{}{}
+		if ctx.Err() != nil {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			pos, err := gdal.queryChunk(ctx, chunks[i], options)
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = pos
+		}(i)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// missingKeys the keys not found in pos, as gerror.MissingIDsError, nil if none.
+func missingKeys[PO any](meta *poMeta, keys []any, canonicals []string, pos []*PO) error {
+	found := make(map[string]bool, len(pos))
+	for _, po := range pos {
+		found[canonicalKey(meta.poKeyValues(po))] = true
+	}
+	var missing []any
+	for i, canonical := range canonicals {
+		if !found[canonical] {
+			missing = append(missing, keys[i])
+		}
+	}
+	if len(missing) > 0 {
+		return gerror.MissingIDsErr(meta.structType, missing)
+	}
+	return nil
+}
+
+// distinctKeys remove the duplicated keys in the order of first occurrence, and return the canonical keys.
+func distinctKeys(meta *poMeta, keys []any) ([]any, []string, error) {
+	seen := make(map[string]bool, len(keys))
+	distinct := make([]any, 0, len(keys))
+	canonicals := make([]string, 0, len(keys))
+	for _, key := range keys {
+		keyValues, err := meta.keyValues(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		canonical := canonicalKey(keyValues)
+		if seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+		distinct = append(distinct, key)
+		canonicals = append(canonicals, canonical)
+	}
+	return distinct, canonicals, nil
+}
+
+// chunksOf split keys into chunks of size, the default size if 0, and a single chunk if negative.
+func chunksOf(keys []any, size int) [][]any {
+	if size == 0 {
+		size = defaultChunkSize
+	}
+	if size < 0 || size >= len(keys) {
+		return [][]any{keys}
+	}
+	chunks := make([][]any, 0, (len(keys)+size-1)/size)
+	for begin := 0; begin < len(keys); begin += size {
+		end := begin + size
+		if end > len(keys) {
+			end = len(keys)
+		}
+		chunks = append(chunks, keys[begin:end])
+	}
+	return chunks
+}
+
+// poKeyValues the values of primary key columns of po.
+func (meta *poMeta) poKeyValues(po any) []any {
+	values := make([]any, 0, len(meta.primaryKeys))
+	for _, column := range meta.primaryKeys {
+		values = append(values, meta.valueOf(po, column))
+	}
+	return values
+}
+
+// canonicalKey the text of values of primary key columns, so that keys of different integer types are equal,
+// such as int 1 and int64 1.
+func canonicalKey(values []any) string {
+	texts := make([]string, 0, len(values))
+	for _, value := range values {
+		texts = append(texts, fmt.Sprint(value))
+	}
+	return strings.Join(texts, "\x00")
+}
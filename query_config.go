@@ -1,14 +1,30 @@
 package gdal
 
+import "gorm.io/gorm/clause"
+
 type QueryConfig struct {
 	readMaster bool
 	debug      bool
+	unscoped   bool
+	exprs      []clause.Expression // extra where expressions merged into the where struct's
+	chunkSize  int                 // the max number of primary keys in a statement of MQueryByIDs
+	parallel   int                 // the max number of chunks of MQueryByIDs queried concurrently
+	strict     bool                // MQueryByIDs reports the missing primary keys
+	groups     []string            // the columns grouped by Aggregate
+	aggregates []clause.Expression // the aggregate expressions selected by Aggregate
+	distinct   bool                // Pluck selects the distinct values
+	locking    *clause.Locking     // row locking of the selected records, only in transaction
+	softDelete map[string]any      // Delete marks the records deleted by these columns rather than removes them
+	guard      clause.Expression   // Update only updates the records no less than the floors of guarded columns
+	guarded    []string            // the columns guarded by guard
 
 	// export field
-	Limit   *int
-	Offset  *int
-	Order   *string
-	Selects []string
+	Limit      *int
+	Offset     *int
+	Order      *string
+	Selects    []string
+	Cursor     *string
+	Checkpoint any
 }
 
 type QueryOption func(v *QueryConfig)
@@ -70,3 +86,240 @@ func WithMaster() QueryOption {
 		v.readMaster = true
 	}
 }
+
+// WithDebug log the full SQL and the operation of this call, regardless of the log level.
+//
+// 💡 HINT: ref SetLogConfig.
+//
+// ⚠️  WARNING: values in SQL are logged as well.
+//
+// 🚀 example:
+//
+//	pos, err := userDAL.MQuery(ctx, where, gdal.WithDebug())
+func WithDebug() QueryOption {
+	return func(v *QueryConfig) {
+		v.debug = true
+	}
+}
+
+// WithCursor assign cursor returned by the previous page of MQueryByCursorOpt
+//
+// 💡 HINT: empty cursor means the first page.
+//
+// ⚠️  WARNING: only effective for cursor paging.
+//
+// 🚀 example:
+//
+//	users, next, hasMore, err := userDAL.MQueryByCursorOpt(ctx, where, gslice.Of(gdal.Desc("create_time")), gdal.WithLimit(10), gdal.WithCursor(cursor))
+func WithCursor(cursor string) QueryOption {
+	return func(v *QueryConfig) {
+		v.Cursor = &cursor
+	}
+}
+
+// WithCheckpoint assign the primary key after which Iterate resumes
+//
+// 💡 HINT: records whose primary key is greater than checkpoint will be iterated.
+//
+// ⚠️  WARNING: only effective for Iterate.
+//
+// 🚀 example:
+//
+//	err := userDAL.Iterate(ctx, where, 100, fn, gdal.WithCheckpoint(lastID))
+func WithCheckpoint(id any) QueryOption {
+	return func(v *QueryConfig) {
+		v.Checkpoint = id
+	}
+}
+
+// WithUnscoped include the soft-deleted records
+//
+// 💡 HINT: only effective when PO has a field tagged by `gdal:"soft_delete"`.
+//
+// ⚠️  WARNING:
+//
+// 🚀 example:
+//
+//	users, err := userDAL.MQuery(ctx, where, gdal.WithUnscoped())
+func WithUnscoped() QueryOption {
+	return func(v *QueryConfig) {
+		v.unscoped = true
+	}
+}
+
+// WithForUpdate lock the selected records exclusively until the transaction ends, i.e. `FOR UPDATE`.
+//
+// 💡 HINT: it implies WithMaster, so that the records are not read from replica.
+//
+// ⚠️  WARNING: only in transaction, otherwise gerror.LockingWithoutTxErr returns. Ignored by sqlite.
+// Only for the queries selecting records, such as Find, First, Exist and Pluck. Count, updates, deletes,
+// aggregates and Distinct return gerror.LockingNotSupportedErr, except that MQueryByPagingOpt counts without
+// locking.
+//
+// 🚀 example:
+//
+//	err := gdal.Transaction(ctx, db, func(ctx context.Context) error {
+//		user, err := userDAL.QueryFirst(ctx, where, gdal.WithForUpdate())
+//		...
+//	})
+//
+// SQL: SELECT ... FROM `user` WHERE ... LIMIT 1 FOR UPDATE
+func WithForUpdate() QueryOption {
+	return withLocking(clause.Locking{Strength: "UPDATE"})
+}
+
+// WithForShare lock the selected records in share mode until the transaction ends, i.e. `FOR SHARE`.
+//
+// 💡 HINT: ref WithForUpdate.
+//
+// ⚠️  WARNING: only in transaction and for the queries selecting records, ref WithForUpdate. MySQL supports
+// `FOR SHARE` since 8.0.
+//
+// 🚀 example:
+//
+//	users, err := userDAL.MQuery(ctx, where, gdal.WithForShare())
+//
+// SQL: SELECT ... FROM `user` WHERE ... FOR SHARE
+func WithForShare() QueryOption {
+	return withLocking(clause.Locking{Strength: "SHARE"})
+}
+
+// WithSkipLocked skip the records locked by others rather than waiting, i.e. `SKIP LOCKED`.
+//
+// 💡 HINT: it implies WithForUpdate if neither WithForUpdate nor WithForShare is given. Handy for job queues.
+//
+// ⚠️  WARNING: only in transaction and for the queries selecting records, ref WithForUpdate.
+//
+// 🚀 example:
+//
+//	jobs, err := jobDAL.MQuery(ctx, where, gdal.WithLimit(10), gdal.WithSkipLocked())
+//
+// SQL: SELECT ... FROM `job` WHERE ... LIMIT 10 FOR UPDATE SKIP LOCKED
+func WithSkipLocked() QueryOption {
+	return withLocking(clause.Locking{Options: "SKIP LOCKED"})
+}
+
+// WithNoWait fail immediately if any record is locked by others rather than waiting, i.e. `NOWAIT`.
+//
+// 💡 HINT: ref WithSkipLocked.
+//
+// ⚠️  WARNING: only in transaction and for the queries selecting records, ref WithForUpdate.
+//
+// 🚀 example:
+//
+//	user, err := userDAL.QueryFirst(ctx, where, gdal.WithNoWait())
+//
+// SQL: SELECT ... FROM `user` WHERE ... LIMIT 1 FOR UPDATE NOWAIT
+func WithNoWait() QueryOption {
+	return withLocking(clause.Locking{Options: "NOWAIT"})
+}
+
+// withLocking merge the strength and options of locking, FOR UPDATE by default, and read master.
+func withLocking(locking clause.Locking) QueryOption {
+	return func(v *QueryConfig) {
+		merged := clause.Locking{Strength: "UPDATE"}
+		if v.locking != nil {
+			merged = *v.locking
+		}
+		if len(locking.Strength) > 0 {
+			merged.Strength = locking.Strength
+		}
+		if len(locking.Options) > 0 {
+			merged.Options = locking.Options
+		}
+		v.locking = &merged
+		v.readMaster = true
+	}
+}
+
+// withoutLocking drop the row locking given before, such as for the count of MQueryByPagingOpt.
+func withoutLocking() QueryOption {
+	return func(v *QueryConfig) {
+		v.locking = nil
+	}
+}
+
+// WithChunkSize split the primary keys of MQueryByIDs into chunks of size, each of which is queried by a statement.
+//
+// 💡 HINT: the default chunk size is 500, and size no more than 0 means no chunking.
+//
+// ⚠️  WARNING: only effective for MQueryByIDs and MQueryMapByIDs.
+//
+// 🚀 example:
+//
+//	users, err := userDAL.MQueryByIDs(ctx, ids, gdal.WithChunkSize(100))
+func WithChunkSize(size int) QueryOption {
+	return func(v *QueryConfig) {
+		v.chunkSize = size
+		if size <= 0 {
+			v.chunkSize = -1
+		}
+	}
+}
+
+// WithParallel query at most n chunks of MQueryByIDs concurrently.
+//
+// 💡 HINT: chunks are queried one by one by default, ref WithChunkSize.
+//
+// ⚠️  WARNING: only effective for MQueryByIDs and MQueryMapByIDs, and ignored inside transaction, which
+// holds a single connection.
+//
+// 🚀 example:
+//
+//	users, err := userDAL.MQueryByIDs(ctx, ids, gdal.WithChunkSize(100), gdal.WithParallel(4))
+func WithParallel(n int) QueryOption {
+	return func(v *QueryConfig) {
+		v.parallel = n
+	}
+}
+
+// WithStrict report the primary keys not found by gerror.MissingIDsError, along with the records found.
+//
+// 💡 HINT: check the error by gerror.IsMissingIDsErr.
+//
+// ⚠️  WARNING: only effective for MQueryByIDs and MQueryMapByIDs.
+//
+// 🚀 example:
+//
+//	users, err := userDAL.MQueryByIDs(ctx, ids, gdal.WithStrict())
+func WithStrict() QueryOption {
+	return func(v *QueryConfig) {
+		v.strict = true
+	}
+}
+
+// withExprs append extra where expressions, which will be combined with the where struct by AND.
+func withExprs(exprs ...clause.Expression) QueryOption {
+	return func(v *QueryConfig) {
+		v.exprs = append(v.exprs, exprs...)
+	}
+}
+
+// withAggregates select the group columns and aggregate expressions for Aggregate.
+func withAggregates(groups []string, aggregates ...clause.Expression) QueryOption {
+	return func(v *QueryConfig) {
+		v.groups = append(v.groups, groups...)
+		v.aggregates = append(v.aggregates, aggregates...)
+	}
+}
+
+// withSoftDelete make Delete mark the records deleted by updating attrs rather than remove them.
+func withSoftDelete(attrs map[string]any) QueryOption {
+	return func(v *QueryConfig) {
+		v.softDelete = attrs
+	}
+}
+
+// withGuard make Update only update the records satisfying guard, i.e. no less than the floors of guarded columns.
+func withGuard(guard clause.Expression, guarded []string) QueryOption {
+	return func(v *QueryConfig) {
+		v.guard, v.guarded = guard, guarded
+	}
+}
+
+// withDistinct select the distinct values for Pluck.
+func withDistinct() QueryOption {
+	return func(v *QueryConfig) {
+		v.distinct = true
+	}
+}
@@ -0,0 +1,342 @@
+package gdal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dirac-lee/gdal/gutil/gsql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	redactorMu sync.RWMutex
+	redactor   = MaskRedactor
+)
+
+// Redactor replaces the value of sensitive column in SQL logs and errors.
+type Redactor func(value any) string
+
+// MaskRedactor replaces every sensitive value by `***`.
+func MaskRedactor(value any) string {
+	return "***"
+}
+
+// HashRedactor replaces every sensitive value by its hash, so that logs of the same value can be correlated
+// without revealing it.
+func HashRedactor(value any) string {
+	sum := sha256.Sum256([]byte(fmt.Sprint(value)))
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// SetRedactor set the Redactor globally, MaskRedactor by default.
+//
+// 💡 HINT: the values of columns tagged by `gdal:"sensitive"` in PO, and fields tagged by
+// `sql_sensitive:"true"` in Where and Update struct are sensitive, so are the values compared with sensitive
+// columns when Where is a clause.Expression. They are redacted from the SQL logged by gorm logger, and from
+// the errors returned by DAL, which are also what interceptors see.
+//
+// ⚠️  WARNING: only string and []byte values are redacted from error messages, since a short number
+// can hardly be told from other parts of the message. The PO, Where and Update of Operation are not redacted,
+// so interceptors must not log them as they are.
+//
+// 🚀 example:
+//
+//	type User struct {
+//		ID    int64  `gorm:"column:id"`
+//		Phone string `gorm:"column:phone" gdal:"sensitive"`
+//	}
+//
+//	gdal.SetRedactor(gdal.HashRedactor)
+func SetRedactor(r Redactor) {
+	if r == nil {
+		r = MaskRedactor
+	}
+	redactorMu.Lock()
+	defer redactorMu.Unlock()
+	redactor = r
+}
+
+func getRedactor() Redactor {
+	redactorMu.RLock()
+	defer redactorMu.RUnlock()
+	return redactor
+}
+
+// redactInvoker collect the sensitive values of op before invoker, and redact them from the error after.
+func redactInvoker(invoker Invoker) Invoker {
+	return func(ctx context.Context, op *Operation) error {
+		op.sensitive = sensitiveValuesOf(op)
+		err := invoker(ctx, op)
+		return op.redactError(err)
+	}
+}
+
+// sensitiveValuesOf the non-zero values of sensitive columns in PO (to be created or saved), Where and Update.
+func sensitiveValuesOf(op *Operation) []any {
+	var columns []string
+	var meta *poMeta
+	if rt := structTypeOf(op.PO); rt != nil {
+		if m, err := getStructMeta(rt); err == nil {
+			meta, columns = m, m.sensitive
+		}
+	}
+
+	var values []any
+	if meta != nil && len(columns) > 0 && (op.Kind == OpCreate || op.Kind == OpSave) {
+		values = appendPOSensitiveValues(values, meta, reflect.ValueOf(op.PO))
+	}
+	if expr, isExpr := op.Where.(clause.Expression); isExpr {
+		values = appendExprSensitiveValues(values, expr, columns)
+	} else if op.Where != nil {
+		whereValues, _ := gsql.SensitiveValues(op.Where, columns...) // invalid where fails later
+		values = append(values, whereValues...)
+	}
+	if op.Config != nil {
+		for _, expr := range op.Config.exprs {
+			values = appendExprSensitiveValues(values, expr, columns)
+		}
+	}
+	switch update := op.Update.(type) {
+	case nil:
+	case map[string]any:
+		for _, column := range columns {
+			if expr, ok := update[column].(*caseExpr); ok { // values of MUpdateByIDs
+				for _, value := range expr.values {
+					values = appendSensitiveValue(values, reflect.ValueOf(value))
+				}
+				continue
+			}
+			values = appendSensitiveValue(values, reflect.ValueOf(update[column]))
+		}
+	default:
+		updateValues, _ := gsql.SensitiveValues(update, columns...) // invalid update fails later
+		values = append(values, updateValues...)
+	}
+	return values
+}
+
+// appendExprSensitiveValues append the values compared with sensitive columns in expr, such as clause.Eq and
+// clause.IN. As for clause.Expr, all the values are taken as sensitive if its SQL mentions any sensitive column.
+func appendExprSensitiveValues(values []any, expr clause.Expression, columns []string) []any {
+	if len(columns) == 0 {
+		return values
+	}
+	isSensitive := func(column any) bool {
+		var name string
+		switch column := column.(type) {
+		case clause.Column:
+			name = column.Name
+		case string:
+			name = column
+		}
+		for _, c := range columns {
+			if name == c {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch expr := expr.(type) {
+	case pkWhere:
+		return appendExprSensitiveValues(values, expr.Expression, columns)
+	case clause.AndConditions:
+		for _, e := range expr.Exprs {
+			values = appendExprSensitiveValues(values, e, columns)
+		}
+	case clause.OrConditions:
+		for _, e := range expr.Exprs {
+			values = appendExprSensitiveValues(values, e, columns)
+		}
+	case clause.NotConditions:
+		for _, e := range expr.Exprs {
+			values = appendExprSensitiveValues(values, e, columns)
+		}
+	case clause.Eq, clause.Neq, clause.Gt, clause.Gte, clause.Lt, clause.Lte, clause.Like:
+		rv := reflect.ValueOf(expr) // all of them are defined as clause.Eq
+		if isSensitive(rv.FieldByName("Column").Interface()) {
+			values = appendSensitiveValue(values, rv.FieldByName("Value").Elem())
+		}
+	case clause.IN:
+		if isSensitive(expr.Column) {
+			for _, value := range expr.Values {
+				values = appendSensitiveValue(values, reflect.ValueOf(value))
+			}
+		}
+	case clause.Expr:
+		values = appendVarsSensitiveValues(values, expr.SQL, expr.Vars, columns)
+	case clause.NamedExpr:
+		values = appendVarsSensitiveValues(values, expr.SQL, expr.Vars, columns)
+	}
+	return values
+}
+
+// appendVarsSensitiveValues append the vars of raw SQL, all of them if the SQL mentions any sensitive column,
+// since a var can not be told which column it is compared with.
+func appendVarsSensitiveValues(values []any, sql string, vars []any, columns []string) []any {
+	mentioned := false
+	for _, column := range columns {
+		if mentionsIdentifier(sql, column) {
+			mentioned = true
+			break
+		}
+	}
+	for _, v := range vars {
+		if expr, ok := v.(clause.Expression); ok {
+			values = appendExprSensitiveValues(values, expr, columns)
+			continue
+		}
+		if !mentioned {
+			continue
+		}
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+			for i := 0; i < rv.Len(); i++ { // expanded into vars by gorm, such as `IN (?)`
+				values = appendSensitiveValue(values, rv.Index(i))
+			}
+			continue
+		}
+		values = appendSensitiveValue(values, reflect.ValueOf(v))
+	}
+	return values
+}
+
+// mentionsIdentifier whether sql mentions identifier as a whole word, such as `phone` in "`phone` = ?" but not in
+// "phone_type = ?".
+func mentionsIdentifier(sql string, identifier string) bool {
+	isWordByte := func(b byte) bool {
+		return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+	}
+	for i := 0; ; {
+		j := strings.Index(sql[i:], identifier)
+		if j < 0 {
+			return false
+		}
+		begin, end := i+j, i+j+len(identifier)
+		if (begin == 0 || !isWordByte(sql[begin-1])) && (end == len(sql) || !isWordByte(sql[end])) {
+			return true
+		}
+		i = begin + 1
+	}
+}
+
+func appendPOSensitiveValues(values []any, meta *poMeta, rv reflect.Value) []any {
+	rv = reflect.Indirect(rv)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			values = appendPOSensitiveValues(values, meta, rv.Index(i))
+		}
+	case reflect.Struct:
+		if rv.Type() != meta.structType {
+			return values
+		}
+		for _, column := range meta.sensitive {
+			values = appendSensitiveValue(values, rv.Field(meta.column2Index[column]))
+		}
+	}
+	return values
+}
+
+func appendSensitiveValue(values []any, rv reflect.Value) []any {
+	rv = reflect.Indirect(rv)
+	if !rv.IsValid() || rv.IsZero() {
+		return values
+	}
+	return append(values, rv.Interface())
+}
+
+// redactVar redact v if it is sensitive, or contains sensitive string, such as `%value%` of like.
+func (op *Operation) redactVar(v any, r Redactor) any {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		v = rv.Elem().Interface()
+	}
+	for _, value := range op.sensitive {
+		if reflect.DeepEqual(v, value) {
+			return r(value)
+		}
+	}
+	if s, ok := v.(string); ok {
+		return op.redactString(s, r)
+	}
+	return v
+}
+
+// redactString replace the sensitive string and []byte values in s.
+func (op *Operation) redactString(s string, r Redactor) string {
+	for _, value := range op.sensitive {
+		var sensitive string
+		switch value := value.(type) {
+		case string:
+			sensitive = value
+		case []byte:
+			sensitive = string(value)
+		}
+		if sensitive != "" {
+			s = strings.ReplaceAll(s, sensitive, r(value))
+		}
+	}
+	return s
+}
+
+func (op *Operation) redactError(err error) error {
+	if err == nil || len(op.sensitive) == 0 {
+		return err
+	}
+	msg := op.redactString(err.Error(), getRedactor())
+	if msg == err.Error() {
+		return err
+	}
+	return &redactedError{msg: msg, err: err}
+}
+
+// redactedError error with sensitive values redacted from its message.
+//
+// 💡 HINT: errors.Is still works on the original error, but it is not unwrapped to avoid leaking.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Is(target error) bool {
+	return errors.Is(e.err, target)
+}
+
+// redactLogger gorm logger redacting the sensitive values of op from SQL and error.
+type redactLogger struct {
+	logger.Interface
+	op *Operation
+}
+
+func (l *redactLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &redactLogger{Interface: l.Interface.LogMode(level), op: l.op}
+}
+
+func (l *redactLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	l.Interface.Trace(ctx, begin, fc, l.op.redactError(err))
+}
+
+// ParamsFilter called by gorm before explaining SQL with params for logging.
+func (l *redactLogger) ParamsFilter(ctx context.Context, sql string, params ...any) (string, []any) {
+	if filter, ok := l.Interface.(gorm.ParamsFilter); ok {
+		sql, params = filter.ParamsFilter(ctx, sql, params...)
+	}
+	r := getRedactor()
+	redacted := make([]any, len(params))
+	for i, param := range params {
+		redacted[i] = l.op.redactVar(param, r)
+	}
+	return sql, redacted
+}
@@ -52,15 +52,21 @@ func getSelectorFromStructTypeSlow(structType reflect.Type) ([]string, error) {
 }
 
 // getKVsFromTag translate tag formatted of `k1:v1;k2:v2;...` to kv map
+//
+// 💡 HINT: the value of flag such as `primaryKey` is empty.
 func getKVsFromTag(tag string) (map[string]string, error) {
+	if len(tag) == 0 {
+		return nil, gerror.GormTagShouldBeKVsErr(tag)
+	}
 	kvs := strings.Split(tag, ";")
 	kvMap := make(map[string]string, len(kvs))
 	for _, s := range kvs {
-		kv := strings.Split(s, ":")
-		if len(kv) != 2 {
-			return nil, gerror.GormTagShouldBeKVsErr(tag)
+		s = strings.TrimSpace(s)
+		if len(s) == 0 {
+			continue
 		}
-		kvMap[kv[0]] = kv[1]
+		k, v, _ := strings.Cut(s, ":")
+		kvMap[strings.TrimSpace(k)] = strings.TrimSpace(v)
 	}
 	return kvMap, nil
 }
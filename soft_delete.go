@@ -0,0 +1,59 @@
+package gdal
+
+import (
+	"reflect"
+
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"gorm.io/gorm/clause"
+)
+
+// checkSoftDeleteField the field tagged by `gdal:"soft_delete"` must be bool or integer
+func checkSoftDeleteField(structField reflect.StructField) error {
+	switch softDeleteKind(structField.Type) {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return nil
+	default:
+		return gerror.GDALErrorf("field (%s) tagged by soft_delete must be bool or integer, but got %v", structField.Name, structField.Type)
+	}
+}
+
+func softDeleteKind(rt reflect.Type) reflect.Kind {
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	return rt.Kind()
+}
+
+// softDeleteValue the value of soft-delete column, `true` or `1` when deleted, otherwise `false` or `0`.
+func (meta *poMeta) softDeleteValue(deleted bool) any {
+	rt := meta.fieldType(meta.softDelete)
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt.Kind() == reflect.Bool {
+		return reflect.ValueOf(deleted).Convert(rt).Interface()
+	}
+	if deleted {
+		return reflect.ValueOf(1).Convert(rt).Interface()
+	}
+	return reflect.Zero(rt).Interface()
+}
+
+// softDeleteExpr the predicate of records which are (not) deleted
+func (meta *poMeta) softDeleteExpr(deleted bool) clause.Expression {
+	return clause.Eq{Column: clause.Column{Name: meta.softDelete}, Value: meta.softDeleteValue(deleted)}
+}
+
+// scopeIfSoftDelete if PO has soft-delete column, filter out the deleted records unless WithUnscoped.
+func (gdal *GDAL[PO, Where, Update]) scopeIfSoftDelete(options []QueryOption) ([]QueryOption, error) {
+	meta, err := getPOMeta[PO]()
+	if err != nil {
+		return nil, err
+	}
+	if len(meta.softDelete) == 0 || MakeQueryConfig(options).unscoped {
+		return options, nil
+	}
+	return append(options[:len(options):len(options)], withExprs(meta.softDeleteExpr(false))), nil
+}
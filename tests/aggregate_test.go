@@ -0,0 +1,95 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAggregate(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		name := uniqueName("aggregate")
+		for _, user := range []*tests.User{
+			{Name: name, Age: 10, CompanyID: gptr.Of(1)},
+			{Name: name, Age: 20, CompanyID: gptr.Of(1)},
+			{Name: name, Age: 60, CompanyID: gptr.Of(2)},
+			{Name: name, Age: 90, CompanyID: gptr.Of(2), IsDeleted: true},
+		} {
+			So(UserDAL.Create(ctx, user), ShouldBeNil)
+		}
+		where := &tests.UserWhere{Name: gptr.Of(name)}
+
+		Convey("sum, max, min and avg", func() {
+			sum, err := gdal.Sum[int64](ctx, UserDAL, where, "age")
+			So(err, ShouldBeNil)
+			So(sum, ShouldEqual, 90)
+			maxAge, err := gdal.Max[uint](ctx, UserDAL, where, "age")
+			So(err, ShouldBeNil)
+			So(maxAge, ShouldEqual, 60)
+			minAge, err := gdal.Min[uint](ctx, UserDAL, where, "age")
+			So(err, ShouldBeNil)
+			So(minAge, ShouldEqual, 10)
+			avg, err := UserDAL.Avg(ctx, where, "age")
+			So(err, ShouldBeNil)
+			So(avg, ShouldEqual, 30)
+		})
+
+		Convey("no record", func() {
+			sum, err := gdal.Sum[int64](ctx, UserDAL, &tests.UserWhere{Name: gptr.Of(name + "-none")}, "age")
+			So(err, ShouldBeNil)
+			So(sum, ShouldEqual, 0)
+		})
+
+		Convey("column not found", func() {
+			_, err := gdal.Sum[int64](ctx, UserDAL, where, "balance")
+			So(err, ShouldNotBeNil)
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+		})
+
+		Convey("group count", func() {
+			rows, err := UserDAL.GroupCount(ctx, where, []string{"company_id"}, gdal.WithOrder("company_id"))
+			So(err, ShouldBeNil)
+			So(len(rows), ShouldEqual, 2)
+			So(rows[0].Group["company_id"], ShouldEqual, 1)
+			So(rows[0].Count, ShouldEqual, 2)
+			So(rows[1].Group["company_id"], ShouldEqual, 2)
+			So(rows[1].Count, ShouldEqual, 1)
+		})
+
+		Convey("aggregate into struct", func() {
+			type CompanyStat struct {
+				CompanyID int     `gorm:"column:company_id"`
+				Total     int64   `gorm:"column:total" sql_agg:"count(*)"`
+				AgeSum    *int64  `sql_agg:"sum(age)"`
+				AvgAge    float64 `gorm:"column:avg_age" sql_agg:"AVG(age)"`
+				Ages      int64   `gorm:"column:ages" sql_agg:"count(distinct age)"`
+			}
+			var stats []*CompanyStat
+			So(UserDAL.Aggregate(ctx, where, &stats, gdal.WithOrder("total desc, company_id")), ShouldBeNil)
+			So(len(stats), ShouldEqual, 2)
+			So(*stats[0], ShouldResemble, CompanyStat{CompanyID: 1, Total: 2, AgeSum: gptr.Of[int64](30), AvgAge: 15, Ages: 2})
+			So(*stats[1], ShouldResemble, CompanyStat{CompanyID: 2, Total: 1, AgeSum: gptr.Of[int64](60), AvgAge: 60, Ages: 1})
+
+			var total struct {
+				Total int64 `gorm:"column:total" sql_agg:"count(*)"`
+			}
+			So(UserDAL.Aggregate(ctx, where, &total), ShouldBeNil)
+			So(total.Total, ShouldEqual, 3)
+		})
+
+		Convey("invalid sql_agg", func() {
+			var invalid struct {
+				Total int64 `gorm:"column:total" sql_agg:"count(*) from user"`
+			}
+			So(UserDAL.Aggregate(ctx, where, &invalid), ShouldNotBeNil)
+			var none struct {
+				CompanyID int `gorm:"column:company_id"`
+			}
+			So(UserDAL.Aggregate(ctx, where, &none), ShouldNotBeNil)
+		})
+	})
+}
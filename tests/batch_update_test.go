@@ -0,0 +1,116 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMUpdateByIDs(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		name := uniqueName("mupdate")
+		var users []*tests.User
+		for i := 0; i < 3; i++ {
+			user := &tests.User{Name: name, Age: uint(10 * (i + 1))}
+			So(UserDAL.Create(ctx, user), ShouldBeNil)
+			users = append(users, user)
+		}
+		load := func(id int64) tests.User {
+			var got tests.User
+			So(DB.First(&got, id).Error, ShouldBeNil)
+			return got
+		}
+
+		Convey("different values by id", func() {
+			numUpdated, err := gdal.MUpdateByIDs(ctx, UserDAL, map[int64]*tests.UserUpdate{
+				users[0].ID: {Age: gptr.Of[uint](1), Active: gptr.Of(true)},
+				users[1].ID: {Age: gptr.Of[uint](2)},
+				users[2].ID: {AgeDecr: nil, Name: gptr.Of(name + "-x")},
+			})
+			So(err, ShouldBeNil)
+			So(numUpdated, ShouldEqual, 3)
+
+			got := load(users[0].ID)
+			So(got.Age, ShouldEqual, 1)
+			So(got.Active, ShouldBeTrue)
+			So(got.Version, ShouldEqual, users[0].Version+1)
+			got = load(users[1].ID)
+			So(got.Age, ShouldEqual, 2)
+			So(got.Active, ShouldBeFalse)
+			got = load(users[2].ID)
+			So(got.Age, ShouldEqual, 30)
+			So(got.Name, ShouldEqual, name+"-x")
+		})
+
+		Convey("sql_expr updaters and NULL", func() {
+			articleDAL := gdal.NewGDAL[tests.Article, tests.ArticleWhere, tests.ArticleUpdate](DB)
+			a1 := &tests.Article{Title: name, Tags: `[]`, Meta: `{}`, Views: 1}
+			a2 := &tests.Article{Title: name, Tags: `[]`, Meta: `{}`, Views: 2}
+			So(articleDAL.Create(ctx, a1), ShouldBeNil)
+			So(articleDAL.Create(ctx, a2), ShouldBeNil)
+			numUpdated, err := gdal.MUpdateByIDs(ctx, articleDAL, map[int64]*tests.ArticleUpdate{
+				a1.ID: {ViewsIncr: gptr.Of[int64](10)},
+				a2.ID: {ViewsMul: gptr.Of[int64](10), TagsAppend: []string{"go"}},
+			})
+			So(err, ShouldBeNil)
+			So(numUpdated, ShouldEqual, 2)
+
+			var got []*tests.Article
+			So(DB.Order("id").Find(&got, []int64{a1.ID, a2.ID}).Error, ShouldBeNil)
+			So(got[0].Views, ShouldEqual, 11)
+			So(got[0].Tags, ShouldEqual, `[]`)
+			So(got[1].Views, ShouldEqual, 20)
+			So(got[1].Tags, ShouldEqual, `["go"]`)
+		})
+
+		Convey("large batches", func() {
+			updates := make(map[int64]*tests.UserUpdate)
+			for i := 0; i < 250; i++ {
+				user := &tests.User{Name: name}
+				So(UserDAL.Create(ctx, user), ShouldBeNil)
+				updates[user.ID] = &tests.UserUpdate{Age: gptr.Of(uint(i))}
+			}
+			numUpdated, err := gdal.MUpdateByIDs(ctx, UserDAL, updates)
+			So(err, ShouldBeNil)
+			So(numUpdated, ShouldEqual, 250)
+			for id, update := range updates {
+				So(load(id).Age, ShouldEqual, *update.Age)
+			}
+		})
+
+		Convey("composite primary key", func() {
+			userRoleDAL := gdal.NewGDAL[tests.UserRole, tests.UserRoleWhere, tests.UserRoleUpdate](DB)
+			userRoles := []*tests.UserRole{
+				{UserID: users[0].ID, RoleCode: "a", Note: "1"},
+				{UserID: users[0].ID, RoleCode: "b", Note: "2"},
+			}
+			_, err := userRoleDAL.MCreate(ctx, &userRoles)
+			So(err, ShouldBeNil)
+			numUpdated, err := gdal.MUpdateByIDs(ctx, userRoleDAL, map[*tests.UserRole]*tests.UserRoleUpdate{
+				&tests.UserRole{UserID: users[0].ID, RoleCode: "a"}: {Note: gptr.Of("x")},
+				&tests.UserRole{UserID: users[0].ID, RoleCode: "b"}: {Note: gptr.Of("y")},
+			})
+			So(err, ShouldBeNil)
+			So(numUpdated, ShouldEqual, 2)
+
+			var notes []string
+			So(DB.Model(&tests.UserRole{}).Where("user_id = ?", users[0].ID).Order("role_code").Pluck("note", &notes).Error, ShouldBeNil)
+			So(notes, ShouldResemble, []string{"x", "y"})
+		})
+
+		Convey("unsupported", func() {
+			_, err := gdal.MUpdateByIDs(ctx, UserDAL, map[int64]*tests.UserUpdate{users[0].ID: {AgeDecr: gptr.Of[uint](1)}})
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+
+			_, err = gdal.MUpdateByIDs(ctx, UserDAL, map[int64]*tests.UserUpdate{users[0].ID: {Version: gptr.Of[int64](1)}})
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+
+			_, err = gdal.MUpdateByIDs(ctx, UserDAL, map[string]*tests.UserUpdate{"110": {Age: gptr.Of[uint](1)}})
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,84 @@
+package tests_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/gutil/gsql"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClaim(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		queue := uniqueName("claim")
+		jobDAL := gdal.NewGDAL[tests.Job, tests.JobWhere, tests.JobUpdate](DB)
+		var jobs []*tests.Job
+		for _, priority := range []int{1, 3, 2} {
+			job := &tests.Job{Queue: queue, Status: "pending", Priority: priority}
+			So(jobDAL.Create(ctx, job), ShouldBeNil)
+			jobs = append(jobs, job)
+		}
+		pending := &tests.JobWhere{Queue: gptr.Of(queue), Status: gptr.Of("pending")}
+		lease := time.Now().Add(time.Minute).Truncate(time.Second)
+		claim := func(owner string, n int) ([]*tests.Job, error) {
+			return gdal.Claim(ctx, jobDAL, pending, &tests.JobUpdate{
+				Status:          gptr.Of("running"),
+				Owner:           gsql.NullableOf(owner),
+				LeaseExpireTime: gsql.NullableOf(lease),
+			}, n, gdal.WithOrder("priority desc"))
+		}
+
+		Convey("claim by order", func() {
+			claimed, err := claim("worker-1", 2)
+			So(err, ShouldBeNil)
+			So(claimed, ShouldHaveLength, 2)
+			So(claimed[0].ID, ShouldEqual, jobs[1].ID)
+			So(claimed[1].ID, ShouldEqual, jobs[2].ID)
+			for _, job := range claimed {
+				So(job.Status, ShouldEqual, "running")
+				So(*job.Owner, ShouldEqual, "worker-1")
+				So(job.LeaseExpireTime.Equal(lease), ShouldBeTrue)
+			}
+
+			claimed, err = claim("worker-2", 2)
+			So(err, ShouldBeNil)
+			So(claimed, ShouldHaveLength, 1)
+			So(claimed[0].ID, ShouldEqual, jobs[0].ID)
+			So(*claimed[0].Owner, ShouldEqual, "worker-2")
+
+			claimed, err = claim("worker-3", 2)
+			So(err, ShouldBeNil)
+			So(claimed, ShouldBeEmpty)
+		})
+
+		Convey("invalid n", func() {
+			_, err := claim("worker-1", 0)
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+		})
+
+		Convey("release expired", func() {
+			_, err := claim("worker-1", 3)
+			So(err, ShouldBeNil)
+			expired := time.Now().Add(-time.Minute)
+			So(jobDAL.Update(ctx, &tests.JobWhere{ID: gptr.Of(jobs[0].ID)}, &tests.JobUpdate{LeaseExpireTime: gsql.NullableOf(expired)}), ShouldBeNil)
+
+			release := &tests.JobUpdate{Status: gptr.Of("pending"), Owner: gsql.Null[string](), LeaseExpireTime: gsql.Null[time.Time]()}
+			running := &tests.JobWhere{Queue: gptr.Of(queue), Status: gptr.Of("running")}
+			numReleased, err := gdal.ReleaseExpired(ctx, jobDAL, running, "lease_expire_time", release)
+			So(err, ShouldBeNil)
+			So(numReleased, ShouldEqual, 1)
+
+			claimed, err := claim("worker-2", 3)
+			So(err, ShouldBeNil)
+			So(claimed, ShouldHaveLength, 1)
+			So(claimed[0].ID, ShouldEqual, jobs[0].ID)
+
+			_, err = gdal.ReleaseExpired(ctx, jobDAL, running, "lease", release)
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+		})
+	})
+}
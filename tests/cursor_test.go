@@ -0,0 +1,100 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMQueryByCursor(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		name := uniqueName("cursor")
+		users := []*tests.User{
+			GetUser(name),
+			GetUser(name),
+			GetUser(name),
+			GetUser(name),
+			GetUser(name),
+		}
+		for i, user := range users {
+			user.Age = uint(20 + i%2)
+		}
+		_, err := UserDAL.MCreate(ctx, &users)
+		So(err, ShouldBeNil)
+
+		where := &tests.UserWhere{
+			Name: gptr.Of(name),
+		}
+
+		Convey("walk through all pages", func() {
+			var (
+				got     []*tests.User
+				cursor  string
+				hasMore = true
+			)
+			for hasMore {
+				var page []*tests.User
+				page, cursor, hasMore, err = UserDAL.MQueryByCursor(ctx, where, cursor, 2, gdal.Desc("age"))
+				So(err, ShouldBeNil)
+				So(len(page), ShouldBeLessThanOrEqualTo, 2)
+				got = append(got, page...)
+			}
+			So(got, ShouldHaveLength, 5)
+			So(cursor, ShouldBeEmpty)
+			var ids []int64
+			for _, user := range got {
+				ids = append(ids, user.ID)
+			}
+			So(ids, ShouldResemble, []int64{users[3].ID, users[1].ID, users[4].ID, users[2].ID, users[0].ID})
+		})
+
+		Convey("mixed directions", func() {
+			page, cursor, hasMore, err := UserDAL.MQueryByCursor(ctx, where, "", 3, gdal.Asc("age"), gdal.Desc("id"))
+			So(err, ShouldBeNil)
+			So(hasMore, ShouldBeTrue)
+			So(page, ShouldHaveLength, 3)
+			So(page[0].ID, ShouldEqual, users[4].ID)
+
+			page, _, hasMore, err = UserDAL.MQueryByCursor(ctx, where, cursor, 3, gdal.Asc("age"), gdal.Desc("id"))
+			So(err, ShouldBeNil)
+			So(hasMore, ShouldBeFalse)
+			So(page, ShouldHaveLength, 2)
+			So(page[0].ID, ShouldEqual, users[3].ID)
+			So(page[1].ID, ShouldEqual, users[1].ID)
+		})
+
+		Convey("tampered cursor", func() {
+			_, cursor, _, err := UserDAL.MQueryByCursor(ctx, where, "", 2, gdal.Desc("age"))
+			So(err, ShouldBeNil)
+			_, _, _, err = UserDAL.MQueryByCursor(ctx, where, cursor+"x", 2, gdal.Desc("age"))
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+			_, _, _, err = UserDAL.MQueryByCursor(ctx, where, cursor, 2, gdal.Asc("age"))
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+		})
+
+		Convey("invalid options", func() {
+			_, _, _, err := UserDAL.MQueryByCursor(ctx, where, "", 2, gdal.Desc("unknown"))
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+			_, _, _, err = UserDAL.MQueryByCursorOpt(ctx, where, []gdal.CursorSort{gdal.Desc("age")})
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+		})
+
+		Convey("cursor secret not set", func() {
+			gdal.SetCursorSecret(nil)
+			defer gdal.SetCursorSecret(cursorSecret)
+			_, _, _, err := UserDAL.MQueryByCursor(ctx, where, "", 2, gdal.Desc("age"))
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+		})
+
+		Convey("nullable sort column", func() {
+			_, _, _, err := UserDAL.MQueryByCursor(ctx, where, "", 2, gdal.Asc("birthday"))
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+			_, _, _, err = UserDAL.MQueryByCursor(ctx, where, "", 2, gdal.Desc("company_id"))
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,46 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// plainDAL a DAL implemented elsewhere, which hides the extended operations of the embedded DAL.
+type plainDAL struct {
+	gdal.DAL
+}
+
+func TestPlainDAL(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		name := uniqueName("plain")
+		userDAL := &gdal.GDAL[tests.User, tests.UserWhere, tests.UserUpdate]{DAL: plainDAL{gdal.NewDAL(DB)}}
+		users := []*tests.User{GetUser(name), GetUser(name)}
+		numCreated, err := userDAL.MCreate(ctx, &users)
+		So(err, ShouldBeNil)
+		So(numCreated, ShouldEqual, 2)
+		where := &tests.UserWhere{Name: gptr.Of(name)}
+
+		Convey("operations without options are supported", func() {
+			numDeleted, err := userDAL.HardDelete(ctx, where)
+			So(err, ShouldBeNil)
+			So(numDeleted, ShouldEqual, 2)
+		})
+
+		Convey("extended operations fail", func() {
+			_, err := gdal.Pluck[tests.User, tests.UserWhere, tests.UserUpdate, string](ctx, userDAL, "name", where)
+			So(err, ShouldNotBeNil)
+
+			_, err = userDAL.Delete(ctx, where) // soft delete and its scope need options
+			So(err, ShouldNotBeNil)
+			_, err = userDAL.MUpdate(ctx, where, &tests.UserUpdate{Age: gptr.Of[uint](20)})
+			So(err, ShouldNotBeNil)
+			count, err := UserDAL.Count(ctx, where)
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+		})
+	})
+}
@@ -0,0 +1,105 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSoftDelete(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		name := uniqueName("delete")
+		users := []*tests.User{
+			GetUser(name),
+			GetUser(name),
+			GetUser(name),
+		}
+		_, err := UserDAL.MCreate(ctx, &users)
+		So(err, ShouldBeNil)
+
+		where := &tests.UserWhere{
+			Name: gptr.Of(name),
+		}
+		numDeleted, err := UserDAL.DeleteByID(ctx, users[0].ID)
+		So(err, ShouldBeNil)
+		So(numDeleted, ShouldEqual, 1)
+
+		Convey("deleted logically", func() {
+			var got tests.User
+			So(DB.First(&got, users[0].ID).Error, ShouldBeNil)
+			So(got.IsDeleted, ShouldBeTrue)
+
+			numDeleted, err := UserDAL.DeleteByID(ctx, users[0].ID)
+			So(err, ShouldBeNil)
+			So(numDeleted, ShouldEqual, 0) // deleted records are not deleted again
+		})
+
+		Convey("queries filter out deleted records", func() {
+			count, err := UserDAL.Count(ctx, where)
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+
+			pos, err := UserDAL.MQuery(ctx, where)
+			So(err, ShouldBeNil)
+			So(pos, ShouldHaveLength, 2)
+
+			exist, err := UserDAL.Exist(ctx, &tests.UserWhere{Name: gptr.Of(name), CreateTimeLT: gptr.Of(users[1].CreateTime)})
+			So(err, ShouldBeNil)
+			So(exist, ShouldBeFalse)
+
+			numUpdated, err := UserDAL.MUpdate(ctx, where, &tests.UserUpdate{Age: gptr.Of[uint](20)})
+			So(err, ShouldBeNil)
+			So(numUpdated, ShouldEqual, 2)
+		})
+
+		Convey("unscoped queries include deleted records", func() {
+			var pos []*tests.User
+			err := UserDAL.Find(ctx, &pos, where, gdal.WithUnscoped())
+			So(err, ShouldBeNil)
+			So(pos, ShouldHaveLength, 3)
+		})
+
+		Convey("restore", func() {
+			numRestored, err := UserDAL.Restore(ctx, where)
+			So(err, ShouldBeNil)
+			So(numRestored, ShouldEqual, 1)
+
+			count, err := UserDAL.Count(ctx, where)
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 3)
+		})
+
+		Convey("hard delete", func() {
+			numDeleted, err := UserDAL.HardDelete(ctx, where)
+			So(err, ShouldBeNil)
+			So(numDeleted, ShouldEqual, 3)
+
+			var count int64
+			So(DB.Model(&tests.User{}).Where("name = ?", name).Count(&count).Error, ShouldBeNil)
+			So(count, ShouldEqual, 0)
+		})
+
+		Convey("empty where is rejected", func() {
+			var live, deleted int64
+			So(DB.Model(&tests.User{}).Where("is_deleted = ?", false).Count(&live).Error, ShouldBeNil)
+			So(DB.Model(&tests.User{}).Where("is_deleted = ?", true).Count(&deleted).Error, ShouldBeNil)
+
+			_, err := UserDAL.Delete(ctx, &tests.UserWhere{})
+			So(err, ShouldNotBeNil)
+			_, err = UserDAL.MUpdate(ctx, &tests.UserWhere{}, &tests.UserUpdate{Age: gptr.Of[uint](1)})
+			So(err, ShouldNotBeNil)
+			So(UserDAL.Update(ctx, &tests.UserWhere{}, &tests.UserUpdate{Age: gptr.Of[uint](1)}), ShouldNotBeNil)
+			_, err = UserDAL.Restore(ctx, &tests.UserWhere{})
+			So(err, ShouldNotBeNil)
+
+			var liveAfter, deletedAfter int64
+			So(DB.Model(&tests.User{}).Where("is_deleted = ?", false).Count(&liveAfter).Error, ShouldBeNil)
+			So(DB.Model(&tests.User{}).Where("is_deleted = ?", true).Count(&deletedAfter).Error, ShouldBeNil)
+			So(liveAfter, ShouldEqual, live)
+			So(deletedAfter, ShouldEqual, deleted)
+		})
+	})
+}
@@ -0,0 +1,91 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/gutil/gslice"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDialect(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		title := uniqueName("dialect")
+		articleDAL := gdal.NewGDAL[tests.Article, tests.ArticleWhere, tests.ArticleUpdate](DB)
+		article := &tests.Article{Title: title, Tags: `["go","sql"]`, Meta: `{"author":"dirac","draft":true}`}
+		So(articleDAL.Create(ctx, article), ShouldBeNil)
+
+		Convey("json_contains", func() {
+			articles, err := articleDAL.MQuery(ctx, &tests.ArticleWhere{Title: gptr.Of(title), TagsContains: gptr.Of(`"go"`)})
+			So(err, ShouldBeNil)
+			So(articles, ShouldHaveLength, 1)
+
+			articles, err = articleDAL.MQuery(ctx, &tests.ArticleWhere{Title: gptr.Of(title), TagsContains: gptr.Of(`["sql","go"]`)})
+			So(err, ShouldBeNil)
+			So(articles, ShouldHaveLength, 1)
+
+			articles, err = articleDAL.MQuery(ctx, &tests.ArticleWhere{Title: gptr.Of(title), TagsContainsAll: []string{`"go"`, `"java"`}})
+			So(err, ShouldBeNil)
+			So(articles, ShouldBeEmpty)
+		})
+
+		Convey("json_set and +", func() {
+			err := articleDAL.Update(ctx, &tests.ArticleWhere{ID: gptr.Of(article.ID)}, &tests.ArticleUpdate{
+				Meta:      &tests.ArticleMeta{Author: gptr.Of("lee")},
+				ViewsIncr: gptr.Of[int64](2),
+			})
+			So(err, ShouldBeNil)
+
+			var got tests.Article
+			So(DB.First(&got, article.ID).Error, ShouldBeNil)
+			So(got.Meta, ShouldEqual, `{"author":"lee","draft":true}`)
+			So(got.Views, ShouldEqual, 2)
+		})
+
+		Convey("arithmetic, clamp and concat", func() {
+			err := articleDAL.Update(ctx, &tests.ArticleWhere{ID: gptr.Of(article.ID)}, &tests.ArticleUpdate{
+				ViewsIncr:   gptr.Of[int64](3),
+				TitleAppend: gptr.Of("!"),
+			})
+			So(err, ShouldBeNil)
+			err = articleDAL.Update(ctx, &tests.ArticleWhere{ID: gptr.Of(article.ID)}, &tests.ArticleUpdate{ViewsMul: gptr.Of[int64](10)})
+			So(err, ShouldBeNil)
+			err = articleDAL.Update(ctx, &tests.ArticleWhere{ID: gptr.Of(article.ID)}, &tests.ArticleUpdate{ViewsAtMost: gptr.Of[int64](20)})
+			So(err, ShouldBeNil)
+
+			var got tests.Article
+			So(DB.First(&got, article.ID).Error, ShouldBeNil)
+			So(got.Views, ShouldEqual, 20)
+			So(got.Title, ShouldEqual, title+"!")
+		})
+
+		Convey("json_array_append, json_remove and json_merge_patch", func() {
+			err := articleDAL.Update(ctx, &tests.ArticleWhere{ID: gptr.Of(article.ID)}, &tests.ArticleUpdate{
+				TagsAppend: []string{"orm"},
+				MetaRemove: []string{"draft"},
+			})
+			So(err, ShouldBeNil)
+			err = articleDAL.Update(ctx, &tests.ArticleWhere{ID: gptr.Of(article.ID)}, &tests.ArticleUpdate{MetaPatch: gptr.Of(`{"lang":"en"}`)})
+			So(err, ShouldBeNil)
+
+			var got tests.Article
+			So(DB.First(&got, article.ID).Error, ShouldBeNil)
+			So(got.Tags, ShouldEqual, `["go","sql","orm"]`)
+			So(got.Meta, ShouldEqual, `{"author":"dirac","lang":"en"}`)
+		})
+
+		Convey("+ on conflict of upsert", func() {
+			conflict := &tests.Article{ID: article.ID, Title: title}
+			err := articleDAL.Upsert(ctx, conflict, gslice.Of("id"), &tests.ArticleUpdate{ViewsIncr: gptr.Of[int64](1)})
+			So(err, ShouldBeNil)
+			err = articleDAL.Upsert(ctx, conflict, gslice.Of("id"), &tests.ArticleUpdate{ViewsIncr: gptr.Of[int64](1)})
+			So(err, ShouldBeNil)
+
+			var got tests.Article
+			So(DB.First(&got, article.ID).Error, ShouldBeNil)
+			So(got.Views, ShouldEqual, 2)
+		})
+	})
+}
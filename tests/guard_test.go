@@ -0,0 +1,85 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUpdateGuard(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		title := uniqueName("guard")
+		articleDAL := gdal.NewGDAL[tests.Article, tests.ArticleWhere, tests.ArticleUpdate](DB)
+		article := &tests.Article{Title: title, Tags: `[]`, Meta: `{}`, Views: 10}
+		So(articleDAL.Create(ctx, article), ShouldBeNil)
+
+		Convey("deduct within the floor", func() {
+			err := articleDAL.UpdateByID(ctx, article.ID, &tests.ArticleUpdate{ViewsDecr: gptr.Of[int64](10)})
+			So(err, ShouldBeNil)
+
+			var got tests.Article
+			So(DB.First(&got, article.ID).Error, ShouldBeNil)
+			So(got.Views, ShouldEqual, 0)
+		})
+
+		Convey("deduct below the floor", func() {
+			err := articleDAL.UpdateByID(ctx, article.ID, &tests.ArticleUpdate{ViewsDecr: gptr.Of[int64](11)})
+			So(gerror.IsInsufficientValueErr(err), ShouldBeTrue)
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+
+			numUpdated, err := articleDAL.MUpdate(ctx, &tests.ArticleWhere{Title: gptr.Of(title)}, &tests.ArticleUpdate{ViewsDecr: gptr.Of[int64](11)})
+			So(gerror.IsInsufficientValueErr(err), ShouldBeTrue)
+			So(numUpdated, ShouldEqual, 0)
+
+			var got tests.Article
+			So(DB.First(&got, article.ID).Error, ShouldBeNil)
+			So(got.Views, ShouldEqual, 10)
+		})
+
+		Convey("deduct from no record", func() {
+			err := articleDAL.UpdateByID(ctx, article.ID+1000000, &tests.ArticleUpdate{ViewsDecr: gptr.Of[int64](11)})
+			So(err, ShouldBeNil)
+
+			numUpdated, err := articleDAL.MUpdate(ctx, &tests.ArticleWhere{Title: gptr.Of(title + "-none")}, &tests.ArticleUpdate{ViewsDecr: gptr.Of[int64](1)})
+			So(err, ShouldBeNil)
+			So(numUpdated, ShouldEqual, 0)
+
+			numUpdated, err = UserDAL.MUpdate(ctx, &tests.UserWhere{Name: gptr.Of(title + "-none")}, &tests.UserUpdate{AgeDecr: gptr.Of[uint](1)})
+			So(err, ShouldBeNil)
+			So(numUpdated, ShouldEqual, 0)
+		})
+
+		Convey("deduct skips the records below the floor", func() {
+			poor := &tests.Article{Title: title, Tags: `[]`, Meta: `{}`, Views: 1}
+			So(articleDAL.Create(ctx, poor), ShouldBeNil)
+
+			numUpdated, err := articleDAL.MUpdate(ctx, &tests.ArticleWhere{Title: gptr.Of(title)}, &tests.ArticleUpdate{ViewsDecr: gptr.Of[int64](5)})
+			So(err, ShouldBeNil)
+			So(numUpdated, ShouldEqual, 1)
+
+			var got tests.Article
+			So(DB.First(&got, poor.ID).Error, ShouldBeNil)
+			So(got.Views, ShouldEqual, 1)
+		})
+
+		Convey("deduct with version", func() {
+			user := &tests.User{Name: title, Age: 3}
+			So(UserDAL.Create(ctx, user), ShouldBeNil)
+
+			err := UserDAL.Update(ctx, &tests.UserWhere{Name: gptr.Of(title)}, &tests.UserUpdate{AgeDecr: gptr.Of[uint](4)})
+			So(gerror.IsInsufficientValueErr(err), ShouldBeTrue)
+
+			err = UserDAL.Update(ctx, &tests.UserWhere{Name: gptr.Of(title)}, &tests.UserUpdate{AgeDecr: gptr.Of[uint](3)})
+			So(err, ShouldBeNil)
+
+			var got tests.User
+			So(DB.First(&got, user.ID).Error, ShouldBeNil)
+			So(got.Age, ShouldEqual, 0)
+			So(got.Version, ShouldEqual, user.Version+1)
+		})
+	})
+}
@@ -0,0 +1,117 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+	"gorm.io/gorm"
+)
+
+type interceptCtxKey struct{}
+
+func TestInterceptor(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		name := uniqueName("intercept")
+		where := &tests.UserWhere{
+			Name: gptr.Of(name),
+		}
+		var ops []string
+		recorder := gdal.InterceptorFunc(func(ctx context.Context, op *gdal.Operation, next gdal.Invoker) error {
+			err := next(ctx, op)
+			ops = append(ops, fmt.Sprintf("%s %s %d", op.Kind, op.Table, op.RowsAffected))
+			return err
+		})
+		userDAL := UserDAL.Use(recorder)
+
+		Convey("all operations pass through", func() {
+			users := []*tests.User{GetUser(name), GetUser(name)}
+			_, err := userDAL.MCreate(ctx, &users)
+			So(err, ShouldBeNil)
+			_, err = userDAL.MQuery(ctx, where)
+			So(err, ShouldBeNil)
+			_, err = userDAL.QueryFirst(ctx, where)
+			So(err, ShouldBeNil)
+			_, err = userDAL.Count(ctx, where)
+			So(err, ShouldBeNil)
+			_, err = userDAL.Exist(ctx, where)
+			So(err, ShouldBeNil)
+			users[0].Age = 30
+			So(userDAL.Save(ctx, users[0]), ShouldBeNil)
+			_, err = userDAL.MUpdate(ctx, where, &tests.UserUpdate{Age: gptr.Of[uint](20)})
+			So(err, ShouldBeNil)
+			_, err = userDAL.HardDelete(ctx, where)
+			So(err, ShouldBeNil)
+
+			So(ops, ShouldResemble, []string{
+				"create user 2",
+				"find user 2",
+				"first user 1",
+				"count user 2",
+				"exist user 1",
+				"update user 1", // save with version
+				"update user 2",
+				"delete user 2",
+			})
+		})
+
+		Convey("interceptors are reserved by WithTx", func() {
+			err := DB.Transaction(func(tx *gorm.DB) error {
+				_, err := userDAL.WithTx(tx).Count(ctx, where)
+				return err
+			})
+			So(err, ShouldBeNil)
+			So(ops, ShouldResemble, []string{"count user 0"})
+		})
+
+		Convey("interceptor can modify or reject operation", func() {
+			user := GetUser(name)
+			So(UserDAL.Create(ctx, user), ShouldBeNil)
+			denied := errors.New("denied")
+			var softDeletes int
+			guardedDAL := UserDAL.Use(gdal.InterceptorFunc(func(ctx context.Context, op *gdal.Operation, next gdal.Invoker) error {
+				if op.Kind == gdal.OpDelete {
+					if op.SoftDelete {
+						softDeletes++
+					}
+					return denied
+				}
+				if op.Kind == gdal.OpFind {
+					op.Config.Limit = gptr.Of(0)
+				}
+				return next(ctx, op)
+			}))
+			_, err := guardedDAL.HardDelete(ctx, where)
+			So(err, ShouldEqual, denied)
+			_, err = guardedDAL.Delete(ctx, where)
+			So(err, ShouldEqual, denied)
+			_, err = guardedDAL.DeleteByID(ctx, user.ID)
+			So(err, ShouldEqual, denied)
+			So(softDeletes, ShouldEqual, 2)
+			count, err := UserDAL.Count(ctx, where)
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+
+			pos, err := guardedDAL.MQuery(ctx, where)
+			So(err, ShouldBeNil)
+			So(pos, ShouldBeEmpty)
+		})
+
+		Convey("global interceptors are called before those of GDAL", func() {
+			gdal.RegisterInterceptor(gdal.InterceptorFunc(func(ctx context.Context, op *gdal.Operation, next gdal.Invoker) error {
+				if ctx.Value(interceptCtxKey{}) != nil {
+					ops = append(ops, "global")
+				}
+				return next(ctx, op)
+			}))
+			_, err := userDAL.Count(context.WithValue(ctx, interceptCtxKey{}, true), where)
+			So(err, ShouldBeNil)
+			So(ops, ShouldResemble, []string{"global", "count user 0"})
+		})
+	})
+}
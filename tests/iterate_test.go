@@ -0,0 +1,103 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIterate(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		name := uniqueName("iterate")
+		var users []*tests.User
+		for i := 0; i < 7; i++ {
+			users = append(users, GetUser(name))
+		}
+		_, err := UserDAL.MCreate(ctx, &users)
+		So(err, ShouldBeNil)
+
+		where := &tests.UserWhere{
+			Name: gptr.Of(name),
+		}
+
+		Convey("walk through all batches", func() {
+			var (
+				batchSizes []int
+				ids        []int64
+			)
+			err := UserDAL.Iterate(ctx, where, 3, func(batch []*tests.User) error {
+				batchSizes = append(batchSizes, len(batch))
+				for _, user := range batch {
+					ids = append(ids, user.ID)
+				}
+				return nil
+			})
+			So(err, ShouldBeNil)
+			So(batchSizes, ShouldResemble, []int{3, 3, 1})
+			So(ids, ShouldHaveLength, 7)
+			So(ids[0], ShouldEqual, users[0].ID)
+			So(ids[6], ShouldEqual, users[6].ID)
+		})
+
+		Convey("resume from checkpoint", func() {
+			var ids []int64
+			err := UserDAL.Iterate(ctx, where, 3, func(batch []*tests.User) error {
+				for _, user := range batch {
+					ids = append(ids, user.ID)
+				}
+				return nil
+			}, gdal.WithCheckpoint(users[4].ID))
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []int64{users[5].ID, users[6].ID})
+		})
+
+		Convey("stop on callback error", func() {
+			stop := errors.New("stop")
+			var calls int
+			err := UserDAL.Iterate(ctx, where, 3, func(batch []*tests.User) error {
+				calls++
+				return stop
+			})
+			So(err, ShouldEqual, stop)
+			So(calls, ShouldEqual, 1)
+		})
+
+		Convey("stop on context cancellation", func() {
+			cancelCtx, cancel := context.WithCancel(ctx)
+			var calls int
+			err := UserDAL.Iterate(cancelCtx, where, 3, func(batch []*tests.User) error {
+				calls++
+				cancel()
+				return nil
+			})
+			So(err, ShouldEqual, context.Canceled)
+			So(calls, ShouldEqual, 1)
+		})
+
+		Convey("composite primary key", func() {
+			userRoleDAL := gdal.NewGDAL[tests.UserRole, tests.UserRoleWhere, tests.UserRoleUpdate](DB)
+			userID := time.Now().UnixNano()
+			_, err := userRoleDAL.MCreate(ctx, &[]*tests.UserRole{
+				{UserID: userID, RoleCode: "c"},
+				{UserID: userID, RoleCode: "a"},
+				{UserID: userID, RoleCode: "b"},
+			})
+			So(err, ShouldBeNil)
+			var codes []string
+			err = userRoleDAL.Iterate(ctx, &tests.UserRoleWhere{UserID: gptr.Of(userID)}, 2, func(batch []*tests.UserRole) error {
+				for _, userRole := range batch {
+					codes = append(codes, userRole.RoleCode)
+				}
+				return nil
+			})
+			So(err, ShouldBeNil)
+			So(codes, ShouldResemble, []string{"a", "b", "c"})
+		})
+	})
+}
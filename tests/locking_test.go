@@ -0,0 +1,98 @@
+package tests_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func TestLocking(t *testing.T) {
+	var locking *clause.Locking // sqlite ignores `FOR`, so capture it before query
+	err := DB.Callback().Query().Before("gorm:query").Register("tests:capture_locking", func(db *gorm.DB) {
+		locking = nil
+		if c, ok := db.Statement.Clauses["FOR"]; ok {
+			if l, ok := c.Expression.(clause.Locking); ok {
+				locking = &l
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { // DB is shared by other tests
+		_ = DB.Callback().Query().Remove("tests:capture_locking")
+	})
+
+	Convey(t.Name(), t, func() {
+		name := uniqueName("locking")
+		So(UserDAL.Create(ctx, &tests.User{Name: name, Age: 10}), ShouldBeNil)
+		where := &tests.UserWhere{Name: gptr.Of(name)}
+
+		Convey("outside transaction", func() {
+			_, err := UserDAL.MQuery(ctx, where, gdal.WithForUpdate())
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+			So(err.Error(), ShouldContainSubstring, "FOR UPDATE")
+			_, err = UserDAL.QueryFirst(ctx, where, gdal.WithSkipLocked())
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+		})
+
+		Convey("in transaction", func() {
+			err := gdal.Transaction(ctx, DB, func(ctx context.Context) error {
+				users, err := UserDAL.MQuery(ctx, where, gdal.WithForUpdate())
+				So(err, ShouldBeNil)
+				So(users, ShouldHaveLength, 1)
+				So(locking, ShouldResemble, &clause.Locking{Strength: "UPDATE"})
+
+				_, err = UserDAL.QueryFirst(ctx, where, gdal.WithForShare(), gdal.WithNoWait())
+				So(err, ShouldBeNil)
+				So(locking, ShouldResemble, &clause.Locking{Strength: "SHARE", Options: "NOWAIT"})
+
+				_, err = UserDAL.MQuery(ctx, where, gdal.WithSkipLocked())
+				So(err, ShouldBeNil)
+				So(locking, ShouldResemble, &clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+				_, err = UserDAL.MQuery(ctx, where)
+				So(err, ShouldBeNil)
+				So(locking, ShouldBeNil)
+				return nil
+			})
+			So(err, ShouldBeNil)
+		})
+
+		Convey("operations not selecting records", func() {
+			err := gdal.Transaction(ctx, DB, func(ctx context.Context) error {
+				_, err := UserDAL.Count(ctx, where, gdal.WithForUpdate())
+				So(gerror.IsGDALErr(err), ShouldBeTrue)
+				_, err = UserDAL.MUpdate(ctx, where, &tests.UserUpdate{Age: gptr.Of[uint](20)}, gdal.WithForUpdate())
+				So(gerror.IsGDALErr(err), ShouldBeTrue)
+				_, err = UserDAL.HardDelete(ctx, where, gdal.WithForUpdate())
+				So(gerror.IsGDALErr(err), ShouldBeTrue)
+				_, err = UserDAL.GroupCount(ctx, where, []string{"age"}, gdal.WithForUpdate())
+				So(gerror.IsGDALErr(err), ShouldBeTrue)
+				_, err = gdal.Distinct[tests.User, tests.UserWhere, tests.UserUpdate, uint](ctx, UserDAL, "age", where, gdal.WithForUpdate())
+				So(gerror.IsGDALErr(err), ShouldBeTrue)
+
+				ages, err := gdal.Pluck[tests.User, tests.UserWhere, tests.UserUpdate, uint](ctx, UserDAL, "age", where, gdal.WithForUpdate())
+				So(err, ShouldBeNil)
+				So(ages, ShouldResemble, []uint{10})
+				users, total, err := UserDAL.MQueryByPagingOpt(ctx, where, gdal.WithLimit(10), gdal.WithForUpdate())
+				So(err, ShouldBeNil)
+				So(users, ShouldHaveLength, 1)
+				So(total, ShouldEqual, 1)
+				return nil
+			})
+			So(err, ShouldBeNil)
+
+			count, err := UserDAL.Count(ctx, where)
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+	})
+}
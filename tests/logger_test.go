@@ -0,0 +1,93 @@
+package tests_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLogger(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		name := uniqueName("logger")
+		var (
+			levels  []gdal.LogLevel
+			entries []*gdal.LogEntry
+		)
+		logger := gdal.LoggerFunc(func(ctx context.Context, level gdal.LogLevel, entry *gdal.LogEntry) {
+			levels = append(levels, level)
+			entries = append(entries, entry)
+		})
+		Reset(func() {
+			gdal.SetLogConfig(gdal.LogConfig{})
+		})
+
+		Convey("log all operations at info level", func() {
+			gdal.SetLogConfig(gdal.LogConfig{Logger: logger, Level: gdal.LogInfo})
+			So(UserDAL.Create(ctx, GetUser(name)), ShouldBeNil)
+			_, err := UserDAL.Count(ctx, &tests.UserWhere{Name: gptr.Of(name), CompanyIDIn: []int{1, 2}})
+			So(err, ShouldBeNil)
+			_, err = UserDAL.Count(ctx, &tests.UserWhere{Name: gptr.Of("another"), CompanyIDIn: []int{3}})
+			So(err, ShouldBeNil)
+
+			So(levels, ShouldResemble, []gdal.LogLevel{gdal.LogInfo, gdal.LogInfo, gdal.LogInfo})
+			So(entries[0].Op, ShouldEqual, gdal.OpCreate)
+			So(entries[0].Table, ShouldEqual, "user")
+			So(entries[0].RowsAffected, ShouldEqual, 1)
+			So(entries[1].Op, ShouldEqual, gdal.OpCount)
+			So(entries[1].RowsAffected, ShouldEqual, 0)
+			So(entries[1].Shape, ShouldContainSubstring, "`name` = ?")
+			So(entries[1].Shape, ShouldContainSubstring, "`company_id` IN (?)")
+			So(entries[1].Shape, ShouldNotContainSubstring, name)
+			So(entries[1].Fingerprint, ShouldNotBeEmpty)
+			So(entries[2].Fingerprint, ShouldEqual, entries[1].Fingerprint) // differ only in values
+		})
+
+		Convey("log failed and slow operations at warn level", func() {
+			gdal.SetLogConfig(gdal.LogConfig{Logger: logger})
+			_, err := UserDAL.Count(ctx, &tests.UserWhere{Name: gptr.Of(name)})
+			So(err, ShouldBeNil)
+			So(entries, ShouldBeEmpty)
+
+			err = UserDAL.Find(ctx, &[]*tests.User{}, &tests.UserWhere{Name: gptr.Of(name)}, gdal.WithOffset(1))
+			So(err, ShouldNotBeNil)
+			So(levels, ShouldResemble, []gdal.LogLevel{gdal.LogError})
+			So(entries[0].Err, ShouldEqual, err)
+
+			gdal.SetLogConfig(gdal.LogConfig{Logger: logger, SlowThreshold: time.Nanosecond})
+			_, err = UserDAL.Count(ctx, &tests.UserWhere{Name: gptr.Of(name)})
+			So(err, ShouldBeNil)
+			So(levels, ShouldResemble, []gdal.LogLevel{gdal.LogError, gdal.LogWarn})
+			So(entries[1].Slow, ShouldBeTrue)
+		})
+
+		Convey("leave SQL errors to gorm logger", func() {
+			gdal.SetLogConfig(gdal.LogConfig{Logger: logger})
+			err := UserDAL.Find(ctx, &[]*tests.User{}, &tests.UserWhere{Name: gptr.Of(name)}, gdal.WithOrder("unknown_column"))
+			So(err, ShouldNotBeNil)
+			So(entries, ShouldBeEmpty)
+
+			gdal.SetLogConfig(gdal.LogConfig{Logger: logger, Level: gdal.LogInfo})
+			err = UserDAL.Find(ctx, &[]*tests.User{}, &tests.UserWhere{Name: gptr.Of(name)}, gdal.WithOrder("unknown_column"))
+			So(err, ShouldNotBeNil)
+			So(levels, ShouldResemble, []gdal.LogLevel{gdal.LogInfo})
+			So(entries[0].Err, ShouldEqual, err)
+			So(entries[0].Shape, ShouldContainSubstring, "`name` = ?")
+		})
+
+		Convey("log operation with debug regardless of level", func() {
+			gdal.SetLogConfig(gdal.LogConfig{Logger: logger, Level: gdal.LogSilent})
+			_, err := UserDAL.Count(ctx, &tests.UserWhere{Name: gptr.Of(name)})
+			So(err, ShouldBeNil)
+			So(entries, ShouldBeEmpty)
+
+			_, err = UserDAL.Count(ctx, &tests.UserWhere{Name: gptr.Of(name)}, gdal.WithDebug())
+			So(err, ShouldBeNil)
+			So(entries, ShouldHaveLength, 1)
+		})
+	})
+}
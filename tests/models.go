@@ -1,7 +1,7 @@
 package tests
 
 import (
-	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/gutil/gsql"
 	"time"
 )
 
@@ -15,7 +15,8 @@ type User struct {
 	Active     bool       `gorm:"column:active"`
 	CreateTime time.Time  `gorm:"column:create_time"`
 	UpdateTime time.Time  `gorm:"column:update_time"`
-	IsDeleted  bool       `gorm:"column:is_deleted"`
+	IsDeleted  bool       `gorm:"column:is_deleted" gdal:"soft_delete"`
+	Version    int64      `gorm:"column:version" gdal:"version"`
 }
 
 func (u User) TableName() string {
@@ -38,8 +39,16 @@ type UserWhere struct {
 	UpdateTimeGE *time.Time `sql_field:"update_time" sql_operator:">="`
 	UpdateTimeLT *time.Time `sql_field:"update_time" sql_operator:"<"`
 
+	BirthdayRange   *gsql.Range[time.Time] `sql_field:"birthday" sql_operator:"between"`
+	CreateTimeRange *gsql.Range[time.Time] `sql_field:"create_time" sql_operator:"between"`
+	AgeOutOfRange   *gsql.Range[uint]      `sql_field:"age" sql_operator:"not between"`
+
 	CompanyIDIn []int  `sql_field:"company_id" sql_operator:"in"`
 	ManagerIDIn []uint `sql_field:"manager_id" sql_operator:"in"`
+
+	IDInRoles *gsql.SubQuery[UserRole, UserRoleWhere] `sql_field:"id" sql_operator:"in subquery" sql_select:"user_id"`
+	HasRole   *gsql.SubQuery[UserRole, UserRoleWhere] `sql_field:"id" sql_operator:"exists" sql_select:"user_id"`
+	HasNoRole *gsql.SubQuery[UserRole, UserRoleWhere] `sql_field:"id" sql_operator:"not exists" sql_select:"user_id"`
 }
 
 func (where UserWhere) ForceIndex() string {
@@ -49,10 +58,6 @@ func (where UserWhere) ForceIndex() string {
 	return ""
 }
 
-func (where *UserWhere) InjectDefault() {
-	where.IsDeleted = gptr.Of(false)
-}
-
 type UserUpdate struct {
 	ID         *int64     `sql_field:"id"`
 	Name       *string    `sql_field:"name"`
@@ -64,4 +69,112 @@ type UserUpdate struct {
 	CreateTime *time.Time `sql_field:"create_time"`
 	UpdateTime *time.Time `sql_field:"update_time"`
 	IsDeleted  *bool      `sql_field:"is_deleted"`
+	Version    *int64     `sql_field:"version"`
+
+	NullableManagerID gsql.Nullable[uint] `sql_field:"manager_id"`
+	AgeDecr           *uint               `sql_field:"age" sql_expr:"-" sql_min:"0"`
+}
+
+type UserRole struct {
+	UserID     int64     `gorm:"column:user_id;primaryKey;autoIncrement:false"`
+	RoleCode   string    `gorm:"column:role_code;primaryKey"`
+	Note       string    `gorm:"column:note"`
+	CreateTime time.Time `gorm:"column:create_time"`
+}
+
+func (ur UserRole) TableName() string {
+	return "user_role"
+}
+
+type UserRoleWhere struct {
+	UserID   *int64  `sql_field:"user_id"`
+	RoleCode *string `sql_field:"role_code"`
+}
+
+type UserRoleUpdate struct {
+	Note *string `sql_field:"note"`
+}
+
+type Role struct {
+	Code   string `gorm:"column:code;primaryKey"`
+	Name   string `gorm:"column:name"`
+	Secret string `gorm:"column:secret" gdal:"sensitive"`
+}
+
+func (r Role) TableName() string {
+	return "role"
+}
+
+type RoleWhere struct {
+	Name     *string `sql_field:"name"`
+	NameLike *string `sql_field:"name" sql_operator:"full like" sql_sensitive:"true"`
+	Secret   *string `sql_field:"secret"`
+}
+
+type RoleUpdate struct {
+	Name   *string `sql_field:"name"`
+	Secret *string `sql_field:"secret"`
+}
+
+type Article struct {
+	ID    int64  `gorm:"column:id"`
+	Title string `gorm:"column:title"`
+	Tags  string `gorm:"column:tags"` // JSON array
+	Meta  string `gorm:"column:meta"` // JSON object
+	Views int64  `gorm:"column:views"`
+}
+
+func (a Article) TableName() string {
+	return "article"
+}
+
+type ArticleWhere struct {
+	ID              *int64   `sql_field:"id"`
+	Title           *string  `sql_field:"title"`
+	TagsContains    *string  `sql_field:"tags" sql_operator:"json_contains"`
+	TagsContainsAll []string `sql_field:"tags" sql_operator:"json_contains all"`
+}
+
+type ArticleMeta struct {
+	Author *string `json:"author"`
+	Draft  *bool   `json:"draft"`
+}
+
+type ArticleUpdate struct {
+	Title     *string      `sql_field:"title"`
+	Meta      *ArticleMeta `sql_field:"meta" sql_expr:"json_set"`
+	ViewsIncr *int64       `sql_field:"views" sql_expr:"+"`
+
+	ViewsMul    *int64   `sql_field:"views" sql_expr:"*"`
+	ViewsAtMost *int64   `sql_field:"views" sql_expr:"least"`
+	TitleAppend *string  `sql_field:"title" sql_expr:"concat"`
+	TagsAppend  []string `sql_field:"tags" sql_expr:"json_array_append"`
+	MetaRemove  []string `sql_field:"meta" sql_expr:"json_remove"`
+	MetaPatch   *string  `sql_field:"meta" sql_expr:"json_merge_patch"`
+	ViewsDecr   *int64   `sql_field:"views" sql_expr:"-" sql_min:"0"`
+}
+
+type Job struct {
+	ID              int64      `gorm:"column:id"`
+	Queue           string     `gorm:"column:queue"`
+	Status          string     `gorm:"column:status"`
+	Owner           *string    `gorm:"column:owner"`
+	LeaseExpireTime *time.Time `gorm:"column:lease_expire_time"`
+	Priority        int        `gorm:"column:priority"`
+}
+
+func (j Job) TableName() string {
+	return "job"
+}
+
+type JobWhere struct {
+	ID     *int64  `sql_field:"id"`
+	Queue  *string `sql_field:"queue"`
+	Status *string `sql_field:"status"`
+}
+
+type JobUpdate struct {
+	Status          *string                  `sql_field:"status"`
+	Owner           gsql.Nullable[string]    `sql_field:"owner"`
+	LeaseExpireTime gsql.Nullable[time.Time] `sql_field:"lease_expire_time"`
 }
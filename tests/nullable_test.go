@@ -0,0 +1,37 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/gutil/gsql"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNullable(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		name := uniqueName("nullable")
+		user := &tests.User{Name: name, ManagerID: gptr.Of[uint](1)}
+		So(UserDAL.Create(ctx, user), ShouldBeNil)
+
+		Convey("set to NULL", func() {
+			err := UserDAL.Update(ctx, &tests.UserWhere{Name: gptr.Of(name)}, &tests.UserUpdate{NullableManagerID: gsql.Null[uint]()})
+			So(err, ShouldBeNil)
+
+			var got tests.User
+			So(DB.First(&got, user.ID).Error, ShouldBeNil)
+			So(got.ManagerID, ShouldBeNil)
+		})
+
+		Convey("unset is left unchanged", func() {
+			err := UserDAL.Update(ctx, &tests.UserWhere{Name: gptr.Of(name)}, &tests.UserUpdate{Age: gptr.Of[uint](18)})
+			So(err, ShouldBeNil)
+
+			var got tests.User
+			So(DB.First(&got, user.ID).Error, ShouldBeNil)
+			So(got.ManagerID, ShouldNotBeNil)
+			So(*got.ManagerID, ShouldEqual, 1)
+		})
+	})
+}
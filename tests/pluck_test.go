@@ -0,0 +1,62 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPluck(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		name := uniqueName("pluck")
+		for _, user := range []*tests.User{
+			{Name: name, Age: 30, CompanyID: gptr.Of(2)},
+			{Name: name, Age: 10, CompanyID: gptr.Of(1)},
+			{Name: name, Age: 20, CompanyID: gptr.Of(2)},
+			{Name: name, Age: 40},
+			{Name: name, Age: 50, CompanyID: gptr.Of(3), IsDeleted: true},
+		} {
+			So(UserDAL.Create(ctx, user), ShouldBeNil)
+		}
+		where := &tests.UserWhere{Name: gptr.Of(name)}
+
+		Convey("pluck", func() {
+			ages, err := gdal.Pluck[tests.User, tests.UserWhere, tests.UserUpdate, uint](ctx, UserDAL, "age", where, gdal.WithOrder("age"))
+			So(err, ShouldBeNil)
+			So(ages, ShouldResemble, []uint{10, 20, 30, 40})
+
+			ages, err = gdal.Pluck[tests.User, tests.UserWhere, tests.UserUpdate, uint](ctx, UserDAL, "age", where, gdal.WithOrder("age desc"), gdal.WithLimit(2), gdal.WithSelects([]string{"name"}))
+			So(err, ShouldBeNil)
+			So(ages, ShouldResemble, []uint{40, 30})
+		})
+
+		Convey("pluck nullable", func() {
+			companyIDs, err := gdal.Pluck[tests.User, tests.UserWhere, tests.UserUpdate, *int](ctx, UserDAL, "company_id", where, gdal.WithOrder("age"))
+			So(err, ShouldBeNil)
+			So(companyIDs, ShouldResemble, []*int{gptr.Of(1), gptr.Of(2), gptr.Of(2), nil})
+		})
+
+		Convey("distinct", func() {
+			companyIDs, err := gdal.Distinct[tests.User, tests.UserWhere, tests.UserUpdate, *int](ctx, UserDAL, "company_id", &tests.UserWhere{Name: gptr.Of(name), CompanyID: nil}, gdal.WithOrder("company_id"))
+			So(err, ShouldBeNil)
+			So(companyIDs, ShouldResemble, []*int{nil, gptr.Of(1), gptr.Of(2)})
+		})
+
+		Convey("no record", func() {
+			ages, err := gdal.Pluck[tests.User, tests.UserWhere, tests.UserUpdate, uint](ctx, UserDAL, "age", &tests.UserWhere{Name: gptr.Of(name + "-none")})
+			So(err, ShouldBeNil)
+			So(ages, ShouldBeEmpty)
+		})
+
+		Convey("column not found", func() {
+			_, err := gdal.Pluck[tests.User, tests.UserWhere, tests.UserUpdate, int64](ctx, UserDAL, "balance", where)
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+			_, err = gdal.Distinct[tests.User, tests.UserWhere, tests.UserUpdate, int64](ctx, UserDAL, "age; DROP TABLE user", where)
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,85 @@
+package tests_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPrimaryKey(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		Convey("string primary key", func() {
+			roleDAL := gdal.NewGDAL[tests.Role, tests.RoleWhere, tests.RoleUpdate](DB)
+			code := uniqueName("role")
+			So(roleDAL.Create(ctx, &tests.Role{Code: code, Name: "admin"}), ShouldBeNil)
+
+			key, err := roleDAL.KeyOf(&tests.Role{Code: code})
+			So(err, ShouldBeNil)
+			So(key, ShouldEqual, code)
+
+			So(roleDAL.UpdateByID(ctx, code, &tests.RoleUpdate{Name: gptr.Of("root")}), ShouldBeNil)
+			var got tests.Role
+			So(DB.First(&got, "code = ?", code).Error, ShouldBeNil)
+			So(got.Name, ShouldEqual, "root")
+
+			numDeleted, err := roleDAL.DeleteByID(ctx, code)
+			So(err, ShouldBeNil)
+			So(numDeleted, ShouldEqual, 1)
+		})
+
+		Convey("mismatched primary key type", func() {
+			roleDAL := gdal.NewGDAL[tests.Role, tests.RoleWhere, tests.RoleUpdate](DB)
+			_, err := roleDAL.DeleteByID(ctx, 110) // an integer is not a string key
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+			err = roleDAL.UpdateByID(ctx, []string{"a"}, &tests.RoleUpdate{Name: gptr.Of("root")})
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+
+			_, err = UserDAL.DeleteByID(ctx, "110")
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+			_, err = UserDAL.DeleteByID(ctx, int32(-1)) // any integer is fine
+			So(err, ShouldBeNil)
+		})
+
+		Convey("composite primary key", func() {
+			userRoleDAL := gdal.NewGDAL[tests.UserRole, tests.UserRoleWhere, tests.UserRoleUpdate](DB)
+			userID := time.Now().UnixNano()
+			userRoles := []*tests.UserRole{
+				{UserID: userID, RoleCode: "a", Note: "1"},
+				{UserID: userID, RoleCode: "b", Note: "2"},
+				{UserID: userID, RoleCode: "c", Note: "3"},
+			}
+			_, err := userRoleDAL.MCreate(ctx, &userRoles)
+			So(err, ShouldBeNil)
+
+			key, err := userRoleDAL.KeyOf(userRoles[1])
+			So(err, ShouldBeNil)
+			So(fmt.Sprintf("%+v", key), ShouldEqual, fmt.Sprintf("{UserID:%d RoleCode:b}", userID))
+
+			So(userRoleDAL.UpdateByID(ctx, key, &tests.UserRoleUpdate{Note: gptr.Of("updated")}), ShouldBeNil)
+			var got tests.UserRole
+			So(DB.First(&got, "user_id = ? AND role_code = ?", userID, "b").Error, ShouldBeNil)
+			So(got.Note, ShouldEqual, "updated")
+
+			numDeleted, err := userRoleDAL.DeleteByID(ctx, &tests.UserRole{UserID: userID, RoleCode: "c"}) // PO is also a key
+			So(err, ShouldBeNil)
+			So(numDeleted, ShouldEqual, 1)
+
+			var codes []string
+			err = userRoleDAL.Iterate(ctx, &tests.UserRoleWhere{UserID: gptr.Of(userID)}, 1, func(batch []*tests.UserRole) error {
+				codes = append(codes, batch[0].RoleCode)
+				return nil
+			})
+			So(err, ShouldBeNil)
+			So(codes, ShouldResemble, []string{"a", "b"})
+
+			err = userRoleDAL.UpdateByID(ctx, userID, &tests.UserRoleUpdate{Note: gptr.Of("x")})
+			So(err, ShouldNotBeNil) // a single value is not a composite key
+		})
+	})
+}
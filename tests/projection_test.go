@@ -0,0 +1,67 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type UserBrief struct {
+	ID   int64  `gorm:"column:id"`
+	Name string `gorm:"column:name"`
+	Age  uint   `gorm:"column:age"`
+}
+
+type UserBalance struct {
+	ID      int64 `gorm:"column:id"`
+	Balance int64 `gorm:"column:balance"`
+}
+
+func TestFindAs(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		name := uniqueName("find-as")
+		var users []*tests.User
+		for _, age := range []uint{30, 10, 20} {
+			user := &tests.User{Name: name, Age: age}
+			So(UserDAL.Create(ctx, user), ShouldBeNil)
+			users = append(users, user)
+		}
+		So(UserDAL.Create(ctx, &tests.User{Name: name, Age: 40, IsDeleted: true}), ShouldBeNil)
+		where := &tests.UserWhere{Name: gptr.Of(name)}
+
+		Convey("find as", func() {
+			briefs, err := gdal.FindAs[UserBrief](ctx, UserDAL, where, gdal.WithOrder("age"))
+			So(err, ShouldBeNil)
+			So(briefs, ShouldResemble, []*UserBrief{
+				{ID: users[1].ID, Name: name, Age: 10},
+				{ID: users[2].ID, Name: name, Age: 20},
+				{ID: users[0].ID, Name: name, Age: 30},
+			})
+
+			briefs, err = gdal.FindAs[UserBrief](ctx, UserDAL, where, gdal.WithOrder("age desc"), gdal.WithLimit(1), gdal.WithOffset(1))
+			So(err, ShouldBeNil)
+			So(briefs, ShouldResemble, []*UserBrief{{ID: users[2].ID, Name: name, Age: 20}})
+		})
+
+		Convey("first as", func() {
+			brief, err := gdal.FirstAs[UserBrief](ctx, UserDAL, where, gdal.WithOrder("age desc"))
+			So(err, ShouldBeNil)
+			So(brief, ShouldResemble, &UserBrief{ID: users[0].ID, Name: name, Age: 30})
+
+			brief, err = gdal.FirstAs[UserBrief](ctx, UserDAL, &tests.UserWhere{Name: gptr.Of(name + "-none")})
+			So(err, ShouldBeNil)
+			So(brief, ShouldBeNil)
+		})
+
+		Convey("column not found", func() {
+			_, err := gdal.FindAs[UserBalance](ctx, UserDAL, where)
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+			_, err = gdal.FirstAs[UserBalance](ctx, UserDAL, where) // checked once
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,116 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMQueryByIDsChunked(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		name := uniqueName("mquery-ids")
+		var ids []int64
+		for i := 0; i < 5; i++ {
+			user := &tests.User{Name: name, Age: uint(i)}
+			So(UserDAL.Create(ctx, user), ShouldBeNil)
+			ids = append(ids, user.ID)
+		}
+		input := []int64{ids[3], ids[0], ids[3], ids[4], ids[1], ids[2], ids[0]}
+		expected := []int64{ids[3], ids[0], ids[4], ids[1], ids[2]}
+		idsOf := func(users []*tests.User) []int64 {
+			var got []int64
+			for _, user := range users {
+				got = append(got, user.ID)
+			}
+			return got
+		}
+
+		Convey("distinct and in the order of ids", func() {
+			users, err := UserDAL.MQueryByIDs(ctx, input)
+			So(err, ShouldBeNil)
+			So(idsOf(users), ShouldResemble, expected)
+		})
+
+		Convey("chunked", func() {
+			users, err := UserDAL.MQueryByIDs(ctx, input, gdal.WithChunkSize(2))
+			So(err, ShouldBeNil)
+			So(idsOf(users), ShouldResemble, expected)
+		})
+
+		Convey("chunked in parallel", func() {
+			users, err := UserDAL.MQueryByIDs(ctx, input, gdal.WithChunkSize(1), gdal.WithParallel(3))
+			So(err, ShouldBeNil)
+			So(idsOf(users), ShouldResemble, expected)
+		})
+
+		Convey("chunked in transaction", func() {
+			err := gdal.Transaction(ctx, DB, func(ctx context.Context) error {
+				users, err := UserDAL.MQueryByIDs(ctx, input, gdal.WithChunkSize(2), gdal.WithParallel(3))
+				So(idsOf(users), ShouldResemble, expected)
+				return err
+			})
+			So(err, ShouldBeNil)
+		})
+
+		Convey("order given", func() {
+			users, err := UserDAL.MQueryByIDs(ctx, input, gdal.WithOrder("age desc"), gdal.WithLimit(2))
+			So(err, ShouldBeNil)
+			So(idsOf(users), ShouldResemble, []int64{ids[4], ids[3]})
+		})
+
+		Convey("empty ids", func() {
+			users, err := UserDAL.MQueryByIDs(ctx, []int64{}, gdal.WithStrict())
+			So(err, ShouldBeNil)
+			So(users, ShouldBeEmpty)
+		})
+
+		Convey("strict", func() {
+			users, err := UserDAL.MQueryByIDs(ctx, []int64{ids[1], -1, ids[0], -2}, gdal.WithStrict(), gdal.WithChunkSize(2))
+			So(gerror.IsMissingIDsErr(err), ShouldBeTrue)
+			var missingErr *gerror.MissingIDsError
+			So(errors.As(err, &missingErr), ShouldBeTrue)
+			So(missingErr.IDs, ShouldResemble, []any{int64(-1), int64(-2)})
+			So(idsOf(users), ShouldResemble, []int64{ids[1], ids[0]})
+
+			_, err = UserDAL.MQueryByIDs(ctx, []int64{ids[1], -1})
+			So(err, ShouldBeNil)
+		})
+
+		Convey("map by ids", func() {
+			userMap, err := gdal.MQueryMapByIDs(ctx, UserDAL, []int{int(ids[2]), int(ids[0]), -1})
+			So(err, ShouldBeNil)
+			So(len(userMap), ShouldEqual, 2)
+			So(userMap[int(ids[2])].Age, ShouldEqual, 2)
+			So(userMap[int(ids[0])].Age, ShouldEqual, 0)
+
+			userMap, err = gdal.MQueryMapByIDs(ctx, UserDAL, []int{int(ids[1]), -1}, gdal.WithStrict())
+			So(gerror.IsMissingIDsErr(err), ShouldBeTrue)
+			So(len(userMap), ShouldEqual, 1)
+		})
+
+		Convey("composite primary key", func() {
+			userRoleDAL := gdal.NewGDAL[tests.UserRole, tests.UserRoleWhere, tests.UserRoleUpdate](DB)
+			userID := time.Now().UnixNano()
+			_, err := userRoleDAL.MCreate(ctx, &[]*tests.UserRole{
+				{UserID: userID, RoleCode: "a"},
+				{UserID: userID, RoleCode: "b"},
+			})
+			So(err, ShouldBeNil)
+			userRoles, err := userRoleDAL.MQueryByIDs(ctx, []*tests.UserRole{
+				{UserID: userID, RoleCode: "b"},
+				{UserID: userID, RoleCode: "a"},
+				{UserID: userID, RoleCode: "b"},
+			}, gdal.WithChunkSize(1))
+			So(err, ShouldBeNil)
+			So(len(userRoles), ShouldEqual, 2)
+			So(userRoles[0].RoleCode, ShouldEqual, "b")
+			So(userRoles[1].RoleCode, ShouldEqual, "a")
+		})
+	})
+}
@@ -81,7 +81,7 @@ func TestFind(t *testing.T) {
 		})
 
 		Convey("QueryByID", func() {
-			user, err := UserDAL.QueryByID(ctx, 123)
+			user, err := UserDAL.QueryByID(ctx, -1) // ids are positive, so that no record is found
 			So(user, ShouldBeNil)
 			So(err, ShouldBeNil)
 		})
@@ -0,0 +1,66 @@
+package tests_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/gutil/gsql"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRange(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		name := uniqueName("range")
+		begin := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		users := []*tests.User{
+			{Name: name, Age: 10, Birthday: gptr.Of(begin)},
+			{Name: name, Age: 20, Birthday: gptr.Of(begin.AddDate(0, 6, 0))},
+			{Name: name, Age: 30, Birthday: gptr.Of(begin.AddDate(1, 0, 0))},
+		}
+		for _, user := range users {
+			So(UserDAL.Create(ctx, user), ShouldBeNil)
+		}
+
+		Convey("between", func() {
+			got, err := UserDAL.MQuery(ctx, &tests.UserWhere{
+				Name:          gptr.Of(name),
+				BirthdayRange: gsql.NewRange(begin, begin.AddDate(1, 0, 0)),
+			})
+			So(err, ShouldBeNil)
+			So(got, ShouldHaveLength, 3)
+
+			got, err = UserDAL.MQuery(ctx, &tests.UserWhere{
+				Name:          gptr.Of(name),
+				BirthdayRange: gsql.NewHalfOpenRange(begin, begin.AddDate(1, 0, 0)),
+			})
+			So(err, ShouldBeNil)
+			So(got, ShouldHaveLength, 2)
+
+			got, err = UserDAL.MQuery(ctx, &tests.UserWhere{
+				Name:          gptr.Of(name),
+				BirthdayRange: &gsql.Range[time.Time]{Min: gptr.Of(begin), MinExclusive: true},
+			})
+			So(err, ShouldBeNil)
+			So(got, ShouldHaveLength, 2)
+		})
+
+		Convey("not between", func() {
+			got, err := UserDAL.MQuery(ctx, &tests.UserWhere{
+				Name:          gptr.Of(name),
+				AgeOutOfRange: gsql.NewRange[uint](15, 25),
+			})
+			So(err, ShouldBeNil)
+			So(got, ShouldHaveLength, 2)
+
+			got, err = UserDAL.MQuery(ctx, &tests.UserWhere{
+				Name:          gptr.Of(name),
+				AgeOutOfRange: &gsql.Range[uint]{Max: gptr.Of[uint](20)},
+			})
+			So(err, ShouldBeNil)
+			So(got, ShouldHaveLength, 1)
+			So(got[0].Age, ShouldEqual, 30)
+		})
+	})
+}
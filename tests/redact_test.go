@@ -0,0 +1,116 @@
+package tests_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+type rejectSecretKey struct{}
+
+var errSecretRejected = errors.New("secret rejected")
+
+func TestRedact(t *testing.T) {
+	// fail creating role with an error revealing its secret, when ctx asks for it
+	err := DB.Callback().Create().Before("gorm:create").Register("tests:reject_secret", func(db *gorm.DB) {
+		if db.Statement.Context.Value(rejectSecretKey{}) == nil {
+			return
+		}
+		if role, ok := db.Statement.Dest.(*tests.Role); ok {
+			_ = db.AddError(fmt.Errorf("%w: %s", errSecretRejected, role.Secret))
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { // DB is shared by other tests
+		_ = DB.Callback().Create().Remove("tests:reject_secret")
+	})
+
+	Convey(t.Name(), t, func() {
+		code := uniqueName("redact")
+		secret := "secret-" + code
+		var buf bytes.Buffer
+		db := DB.Session(&gorm.Session{Logger: logger.New(log.New(&buf, "", 0), logger.Config{LogLevel: logger.Info})})
+		roleDAL := gdal.NewGDAL[tests.Role, tests.RoleWhere, tests.RoleUpdate](db)
+		Reset(func() {
+			gdal.SetRedactor(nil)
+		})
+
+		Convey("redact sensitive values from SQL log", func() {
+			So(roleDAL.Create(ctx, &tests.Role{Code: code, Name: code, Secret: secret}), ShouldBeNil)
+			So(buf.String(), ShouldContainSubstring, code)
+			So(buf.String(), ShouldNotContainSubstring, secret)
+			So(buf.String(), ShouldContainSubstring, "***")
+
+			buf.Reset()
+			roles, err := roleDAL.MQuery(ctx, &tests.RoleWhere{Secret: gptr.Of(secret), NameLike: gptr.Of(code)})
+			So(err, ShouldBeNil)
+			So(roles, ShouldHaveLength, 1)
+			So(buf.String(), ShouldNotContainSubstring, secret)
+			So(buf.String(), ShouldNotContainSubstring, code) // name like is sensitive
+
+			buf.Reset()
+			newSecret := secret + "-new"
+			So(roleDAL.Update(ctx, &tests.RoleWhere{Name: gptr.Of(code)}, &tests.RoleUpdate{Secret: gptr.Of(newSecret)}), ShouldBeNil)
+			So(buf.String(), ShouldContainSubstring, code)
+			So(buf.String(), ShouldNotContainSubstring, newSecret)
+		})
+
+		Convey("redact sensitive values of expression where from SQL log", func() {
+			So(roleDAL.Create(ctx, &tests.Role{Code: code, Name: code, Secret: secret}), ShouldBeNil)
+
+			buf.Reset()
+			var roles []*tests.Role
+			So(roleDAL.Find(ctx, &roles, clause.And(
+				clause.Eq{Column: clause.Column{Name: "secret"}, Value: secret},
+				clause.Eq{Column: clause.Column{Name: "code"}, Value: code},
+			)), ShouldBeNil)
+			So(roles, ShouldHaveLength, 1)
+			So(buf.String(), ShouldContainSubstring, code)
+			So(buf.String(), ShouldNotContainSubstring, secret)
+
+			buf.Reset()
+			roles = nil
+			So(roleDAL.Find(ctx, &roles, clause.Expr{SQL: "`secret` IN (?)", Vars: []any{[]string{secret}}}), ShouldBeNil)
+			So(roles, ShouldHaveLength, 1)
+			So(buf.String(), ShouldNotContainSubstring, secret)
+		})
+
+		Convey("redact by hash", func() {
+			gdal.SetRedactor(gdal.HashRedactor)
+			So(roleDAL.Create(ctx, &tests.Role{Code: code, Secret: secret}), ShouldBeNil)
+			So(buf.String(), ShouldNotContainSubstring, secret)
+			So(buf.String(), ShouldContainSubstring, gdal.HashRedactor(secret))
+		})
+
+		Convey("redact sensitive values from error", func() {
+			var seen error
+			interceptedDAL := gdal.NewGDAL[tests.Role, tests.RoleWhere, tests.RoleUpdate](db).Use(
+				gdal.InterceptorFunc(func(ctx context.Context, op *gdal.Operation, next gdal.Invoker) error {
+					seen = next(ctx, op)
+					return seen
+				}),
+			)
+			rejectCtx := context.WithValue(ctx, rejectSecretKey{}, true)
+			err := interceptedDAL.Create(rejectCtx, &tests.Role{Code: code, Secret: secret})
+			So(err, ShouldNotBeNil)
+			So(errors.Is(err, errSecretRejected), ShouldBeTrue)
+			So(err.Error(), ShouldNotContainSubstring, secret)
+			So(err.Error(), ShouldContainSubstring, "***")
+			So(seen, ShouldEqual, err)
+			So(buf.String(), ShouldNotContainSubstring, secret)
+		})
+	})
+}
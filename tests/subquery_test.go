@@ -0,0 +1,81 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/gutil/gsql"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSubQuery(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		name := uniqueName("subquery")
+		role := uniqueName("role")
+		admin := &tests.User{Name: name}
+		guest := &tests.User{Name: name}
+		So(UserDAL.Create(ctx, admin), ShouldBeNil)
+		So(UserDAL.Create(ctx, guest), ShouldBeNil)
+		userRoleDAL := gdal.NewGDAL[tests.UserRole, tests.UserRoleWhere, tests.UserRoleUpdate](DB)
+		So(userRoleDAL.Create(ctx, &tests.UserRole{UserID: admin.ID, RoleCode: role}), ShouldBeNil)
+
+		Convey("in subquery", func() {
+			users, err := UserDAL.MQuery(ctx, &tests.UserWhere{
+				Name:      gptr.Of(name),
+				IDInRoles: gsql.NewSubQuery[tests.UserRole](&tests.UserRoleWhere{RoleCode: gptr.Of(role)}),
+			})
+			So(err, ShouldBeNil)
+			So(users, ShouldHaveLength, 1)
+			So(users[0].ID, ShouldEqual, admin.ID)
+		})
+
+		Convey("exists and not exists", func() {
+			users, err := UserDAL.MQuery(ctx, &tests.UserWhere{
+				Name:    gptr.Of(name),
+				HasRole: gsql.NewSubQuery[tests.UserRole](&tests.UserRoleWhere{RoleCode: gptr.Of(role)}),
+			})
+			So(err, ShouldBeNil)
+			So(users, ShouldHaveLength, 1)
+			So(users[0].ID, ShouldEqual, admin.ID)
+
+			users, err = UserDAL.MQuery(ctx, &tests.UserWhere{
+				Name:      gptr.Of(name),
+				HasNoRole: &gsql.SubQuery[tests.UserRole, tests.UserRoleWhere]{},
+			})
+			So(err, ShouldBeNil)
+			So(users, ShouldHaveLength, 1)
+			So(users[0].ID, ShouldEqual, guest.ID)
+		})
+
+		Convey("exists on the same table filters out soft-deleted records", func() {
+			report := &tests.User{Name: name, ManagerID: gptr.Of(uint(admin.ID))}
+			So(UserDAL.Create(ctx, report), ShouldBeNil)
+			where := &managerWhere{
+				Name:       gptr.Of(name),
+				HasReports: gsql.NewSubQuery[tests.User](&tests.UserWhere{}),
+			}
+			var users []*tests.User
+			So(UserDAL.Find(ctx, &users, where), ShouldBeNil)
+			So(users, ShouldHaveLength, 1)
+			So(users[0].ID, ShouldEqual, admin.ID)
+
+			_, err := UserDAL.DeleteByID(ctx, report.ID)
+			So(err, ShouldBeNil)
+			users = nil
+			So(UserDAL.Find(ctx, &users, where), ShouldBeNil)
+			So(users, ShouldBeEmpty)
+
+			where.HasReports.Unscoped = true
+			So(UserDAL.Find(ctx, &users, where), ShouldBeNil)
+			So(users, ShouldHaveLength, 1)
+		})
+	})
+}
+
+// managerWhere where struct with subquery on the same table
+type managerWhere struct {
+	Name       *string                                     `sql_field:"name"`
+	HasReports *gsql.SubQuery[tests.User, tests.UserWhere] `sql_field:"id" sql_operator:"exists" sql_select:"manager_id"`
+}
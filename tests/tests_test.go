@@ -2,6 +2,7 @@ package tests_test
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"math/rand"
 	"os"
@@ -22,6 +23,8 @@ import (
 )
 
 var (
+	cursorSecret = []byte("gdal-tests-cursor-secret")
+
 	DB      *gorm.DB
 	UserDAL *gdal.GDAL[tests.User, tests.UserWhere, tests.UserUpdate]
 	ctx     = context.Background()
@@ -58,6 +61,13 @@ func init() {
 		}
 	}
 	UserDAL = gdal.NewGDAL[tests.User, tests.UserWhere, tests.UserUpdate](DB)
+	gdal.SetCursorSecret(cursorSecret)
+}
+
+// uniqueName name prefixed by prefix and unique among runs, so that records created by each leaf convey,
+// which runs the setup again, are isolated from others.
+func uniqueName(prefix string) string {
+	return fmt.Sprintf("%s-%d", prefix, time.Now().UnixNano())
 }
 
 func OpenTestConnection() (db *gorm.DB, err error) {
@@ -118,7 +128,7 @@ func OpenTestConnection() (db *gorm.DB, err error) {
 
 func RunMigrations() {
 	var err error
-	allModels := []interface{}{&tests.User{}, &Account{}, &Pet{}, &Company{}, &Toy{}, &Language{}, &Coupon{}, &CouponProduct{}, &Order{}, &Parent{}, &Child{}}
+	allModels := []interface{}{&tests.User{}, &tests.UserRole{}, &tests.Role{}, &tests.Article{}, &tests.Job{}, &Account{}, &Pet{}, &Company{}, &Toy{}, &Language{}, &Coupon{}, &CouponProduct{}, &Order{}, &Parent{}, &Child{}}
 	rand.Seed(time.Now().UnixNano())
 	rand.Shuffle(len(allModels), func(i, j int) { allModels[i], allModels[j] = allModels[j], allModels[i] })
 
@@ -0,0 +1,108 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dirac-lee/gdal"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTransaction(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		name := uniqueName("transaction")
+		where := &tests.UserWhere{
+			Name: gptr.Of(name),
+		}
+		roleDAL := gdal.NewGDAL[tests.Role, tests.RoleWhere, tests.RoleUpdate](DB)
+		rollback := errors.New("rollback")
+		var events []string
+
+		Convey("commit", func() {
+			err := gdal.Transaction(ctx, DB, func(ctx context.Context) error {
+				if err := UserDAL.Create(ctx, GetUser(name)); err != nil {
+					return err
+				}
+				if err := roleDAL.Create(ctx, &tests.Role{Code: name}); err != nil {
+					return err
+				}
+				count, err := UserDAL.Count(ctx, where) // read in transaction
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 1)
+
+				gdal.AfterCommit(ctx, func(ctx context.Context) { events = append(events, "commit") })
+				gdal.AfterRollback(ctx, func(ctx context.Context) { events = append(events, "rollback") })
+				return nil
+			})
+			So(err, ShouldBeNil)
+			So(events, ShouldResemble, []string{"commit"})
+
+			count, err := UserDAL.Count(ctx, where)
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+			count, err = roleDAL.Count(ctx, &tests.RoleWhere{})
+			So(err, ShouldBeNil)
+			So(count, ShouldBeGreaterThan, 0)
+		})
+
+		Convey("rollback", func() {
+			err := gdal.Transaction(ctx, DB, func(ctx context.Context) error {
+				if err := UserDAL.Create(ctx, GetUser(name)); err != nil {
+					return err
+				}
+				gdal.AfterCommit(ctx, func(ctx context.Context) { events = append(events, "commit") })
+				gdal.AfterRollback(ctx, func(ctx context.Context) { events = append(events, "rollback") })
+				return rollback
+			})
+			So(err, ShouldEqual, rollback)
+			So(events, ShouldResemble, []string{"rollback"})
+
+			count, err := UserDAL.Count(ctx, where)
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 0)
+		})
+
+		Convey("nested transaction is savepoint", func() {
+			err := gdal.Transaction(ctx, DB, func(ctx context.Context) error {
+				if err := UserDAL.Create(ctx, GetUser(name)); err != nil {
+					return err
+				}
+				err := gdal.Transaction(ctx, DB, func(ctx context.Context) error {
+					if err := UserDAL.Create(ctx, GetUser(name)); err != nil {
+						return err
+					}
+					gdal.AfterCommit(ctx, func(ctx context.Context) { events = append(events, "inner commit") })
+					gdal.AfterRollback(ctx, func(ctx context.Context) { events = append(events, "inner rollback") })
+					return rollback
+				})
+				So(err, ShouldEqual, rollback)
+
+				err = gdal.Transaction(ctx, DB, func(ctx context.Context) error {
+					gdal.AfterCommit(ctx, func(ctx context.Context) { events = append(events, "released commit") })
+					return UserDAL.Create(ctx, GetUser(name))
+				})
+				So(err, ShouldBeNil)
+				gdal.AfterCommit(ctx, func(ctx context.Context) { events = append(events, "outer commit") })
+				return nil
+			})
+			So(err, ShouldBeNil)
+			So(events, ShouldResemble, []string{"inner rollback", "released commit", "outer commit"})
+
+			count, err := UserDAL.Count(ctx, where)
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+		})
+
+		Convey("without transaction", func() {
+			gdal.AfterCommit(ctx, func(ctx context.Context) { events = append(events, "commit") })
+			gdal.AfterRollback(ctx, func(ctx context.Context) { events = append(events, "rollback") })
+			So(events, ShouldResemble, []string{"commit"})
+
+			_, ok := gdal.TxFromCtx(ctx)
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
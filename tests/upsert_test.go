@@ -0,0 +1,83 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/gutil/gslice"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUpsert(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		user := GetUser("upsert")
+		So(UserDAL.Create(ctx, user), ShouldBeNil)
+
+		Convey("apply update on conflict", func() {
+			conflict := GetUser("upsert-conflict")
+			conflict.ID = user.ID
+			conflict.Age = 99
+			err := UserDAL.Upsert(ctx, conflict, gslice.Of("id"), &tests.UserUpdate{
+				Name: gptr.Of("upsert-updated"),
+			})
+			So(err, ShouldBeNil)
+
+			var got tests.User
+			So(DB.First(&got, user.ID).Error, ShouldBeNil)
+			So(got.Name, ShouldEqual, "upsert-updated")
+			So(got.Age, ShouldEqual, user.Age) // fields out of update are untouched
+		})
+
+		Convey("ignore conflict when update is empty", func() {
+			conflict := GetUser("upsert-conflict")
+			conflict.ID = user.ID
+			err := UserDAL.Upsert(ctx, conflict, nil, nil)
+			So(err, ShouldBeNil)
+
+			var got tests.User
+			So(DB.First(&got, user.ID).Error, ShouldBeNil)
+			So(got.Name, ShouldEqual, "upsert")
+		})
+
+		Convey("insert multiple records", func() {
+			conflict := GetUser("upsert-conflict")
+			conflict.ID = user.ID
+			users := []*tests.User{conflict, GetUser("upsert-new")}
+			_, err := UserDAL.MUpsert(ctx, &users, gslice.Of("id"), &tests.UserUpdate{
+				Age: gptr.Of[uint](30),
+			})
+			So(err, ShouldBeNil)
+
+			var got tests.User
+			So(DB.First(&got, user.ID).Error, ShouldBeNil)
+			So(got.Name, ShouldEqual, "upsert")
+			So(got.Age, ShouldEqual, 30)
+			So(users[1].ID, ShouldNotBeZeroValue)
+		})
+
+		Convey("reject guarded update", func() {
+			conflict := GetUser("upsert-conflict")
+			conflict.ID = user.ID
+			err := UserDAL.Upsert(ctx, conflict, gslice.Of("id"), &tests.UserUpdate{
+				AgeDecr: gptr.Of[uint](3),
+			})
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+			users := []*tests.User{conflict}
+			_, err = UserDAL.MUpsert(ctx, &users, gslice.Of("id"), &tests.UserUpdate{
+				AgeDecr: gptr.Of[uint](3),
+			})
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+
+			var got tests.User
+			So(DB.First(&got, user.ID).Error, ShouldBeNil)
+			So(got.Age, ShouldEqual, user.Age)
+		})
+
+		Convey("unknown conflict column", func() {
+			err := UserDAL.Upsert(ctx, GetUser("upsert"), gslice.Of("unknown"), nil)
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+		})
+	})
+}
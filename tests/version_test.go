@@ -0,0 +1,129 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"github.com/dirac-lee/gdal/gutil/gptr"
+	"github.com/dirac-lee/gdal/tests"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestVersion(t *testing.T) {
+	Convey(t.Name(), t, func() {
+		name := uniqueName("version")
+		user := GetUser(name)
+		err := UserDAL.Create(ctx, user)
+		So(err, ShouldBeNil)
+		So(user.Version, ShouldEqual, 0)
+
+		Convey("update increases version", func() {
+			err := UserDAL.Update(ctx, &tests.UserWhere{Name: gptr.Of(name)}, &tests.UserUpdate{Age: gptr.Of[uint](20)})
+			So(err, ShouldBeNil)
+
+			var got tests.User
+			So(DB.First(&got, user.ID).Error, ShouldBeNil)
+			So(got.Age, ShouldEqual, 20)
+			So(got.Version, ShouldEqual, 1)
+		})
+
+		Convey("update by id requires expected version", func() {
+			err := UserDAL.UpdateByID(ctx, user.ID, &tests.UserUpdate{Age: gptr.Of[uint](20)})
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+			So(gerror.IsVersionConflictErr(err), ShouldBeFalse)
+
+			var got tests.User
+			So(DB.First(&got, user.ID).Error, ShouldBeNil)
+			So(got.Age, ShouldEqual, user.Age)
+			So(got.Version, ShouldEqual, 0)
+		})
+
+		Convey("delete, restore and upsert increase version", func() {
+			where := &tests.UserWhere{Name: gptr.Of(name)}
+			_, err := UserDAL.Delete(ctx, where)
+			So(err, ShouldBeNil)
+			_, err = UserDAL.Restore(ctx, &tests.UserWhere{Name: gptr.Of(name)})
+			So(err, ShouldBeNil)
+			conflict := GetUser(name)
+			conflict.ID = user.ID
+			So(UserDAL.Upsert(ctx, conflict, nil, &tests.UserUpdate{Age: gptr.Of[uint](25)}), ShouldBeNil)
+
+			var got tests.User
+			So(DB.First(&got, user.ID).Error, ShouldBeNil)
+			So(got.IsDeleted, ShouldBeFalse)
+			So(got.Age, ShouldEqual, 25)
+			So(got.Version, ShouldEqual, 3)
+
+			err = UserDAL.Upsert(ctx, conflict, nil, &tests.UserUpdate{Age: gptr.Of[uint](26), Version: gptr.Of[int64](3)})
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+		})
+
+		Convey("update with expected version", func() {
+			err := UserDAL.UpdateByID(ctx, user.ID, &tests.UserUpdate{Age: gptr.Of[uint](20), Version: gptr.Of[int64](0)})
+			So(err, ShouldBeNil)
+
+			err = UserDAL.UpdateByID(ctx, user.ID, &tests.UserUpdate{Age: gptr.Of[uint](21), Version: gptr.Of[int64](0)})
+			So(gerror.IsVersionConflictErr(err), ShouldBeTrue)
+			So(gerror.IsGDALErr(err), ShouldBeTrue)
+
+			numUpdated, err := UserDAL.MUpdate(ctx, &tests.UserWhere{Name: gptr.Of(name)}, &tests.UserUpdate{Age: gptr.Of[uint](22), Version: gptr.Of[int64](1)})
+			So(err, ShouldBeNil)
+			So(numUpdated, ShouldEqual, 1)
+
+			var got tests.User
+			So(DB.First(&got, user.ID).Error, ShouldBeNil)
+			So(got.Age, ShouldEqual, 22)
+			So(got.Version, ShouldEqual, 2)
+		})
+
+		Convey("save checks version", func() {
+			stale := *user
+			user.Age = 30
+			So(UserDAL.Save(ctx, user), ShouldBeNil)
+			So(user.Version, ShouldEqual, 1)
+
+			stale.Age = 40
+			err := UserDAL.Save(ctx, &stale)
+			So(gerror.IsVersionConflictErr(err), ShouldBeTrue)
+
+			var got tests.User
+			So(DB.First(&got, user.ID).Error, ShouldBeNil)
+			So(got.Age, ShouldEqual, 30)
+			So(got.Version, ShouldEqual, 1)
+		})
+
+		Convey("update with retry", func() {
+			var calls int
+			err := UserDAL.UpdateWithRetry(ctx, user.ID, 1, func(po *tests.User) error {
+				calls++
+				if calls == 1 { // updated concurrently by another worker
+					So(UserDAL.UpdateByID(ctx, user.ID, &tests.UserUpdate{Active: gptr.Of(true), Version: gptr.Of(po.Version)}), ShouldBeNil)
+				}
+				po.Age++
+				return nil
+			})
+			So(err, ShouldBeNil)
+			So(calls, ShouldEqual, 2)
+
+			var got tests.User
+			So(DB.First(&got, user.ID).Error, ShouldBeNil)
+			So(got.Age, ShouldEqual, 19)
+			So(got.Active, ShouldBeTrue)
+			So(got.Version, ShouldEqual, 2)
+		})
+
+		Convey("update with retry exhausted", func() {
+			err := UserDAL.UpdateWithRetry(ctx, user.ID, 1, func(po *tests.User) error {
+				return UserDAL.UpdateByID(ctx, user.ID, &tests.UserUpdate{Age: gptr.Of(po.Age + 1), Version: gptr.Of(po.Version)})
+			})
+			So(gerror.IsVersionConflictErr(err), ShouldBeTrue)
+
+			stop := errors.New("stop")
+			err = UserDAL.UpdateWithRetry(ctx, user.ID, 1, func(po *tests.User) error {
+				return stop
+			})
+			So(err, ShouldEqual, stop)
+		})
+	})
+}
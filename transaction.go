@@ -0,0 +1,144 @@
+package gdal
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// txCtxKey the context key of txScope
+type txCtxKey struct{}
+
+// txScope the transaction (or savepoint if nested) carried by context.
+type txScope struct {
+	tx     *gorm.DB
+	parent *txScope
+
+	mu            sync.Mutex
+	afterCommit   []func(ctx context.Context)
+	afterRollback []func(ctx context.Context)
+}
+
+// Transaction executes fn in a transaction, which is carried by the context passed to fn, so that every
+// GDAL called with that context joins the transaction automatically, without WithTx.
+//
+// 💡 HINT: the transaction is committed when fn returns nil, otherwise, it is rolled back.
+//
+// 💡 HINT: when ctx has carried a transaction already, a savepoint of it is created rather than a new
+// transaction, and db is ignored. Rolling back the savepoint does not roll back the outer transaction.
+//
+// ⚠️  WARNING: only GDAL on the same database as db joins the transaction.
+//
+// 🚀 example:
+//
+//	err := gdal.Transaction(ctx, db, func(ctx context.Context) error {
+//		if err := userDAL.UpdateByID(ctx, 130, update); err != nil {
+//			return err // rollback
+//		}
+//		if _, err := accountDAL.DeleteByID(ctx, 130); err != nil {
+//			return err // rollback
+//		}
+//		gdal.AfterCommit(ctx, func(ctx context.Context) {
+//			notify(130)
+//		})
+//		return nil // commit
+//	})
+func Transaction(ctx context.Context, db *gorm.DB, fn func(ctx context.Context) error, opts ...*sql.TxOptions) error {
+	parent, nested := scopeFromCtx(ctx)
+	if nested {
+		db = parent.tx
+	}
+	scope := &txScope{parent: parent}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		scope.tx = tx
+		return fn(context.WithValue(ctx, txCtxKey{}, scope))
+	}, opts...)
+	if err != nil {
+		scope.rollback(ctx)
+		return err
+	}
+	if nested { // savepoint released, the callbacks depend on the outer transaction.
+		parent.merge(scope)
+		return nil
+	}
+	scope.commit(ctx)
+	return nil
+}
+
+// AfterCommit register fn which is called after the transaction carried by ctx is committed.
+//
+// 💡 HINT: when ctx carries no transaction, fn is called immediately.
+//
+// ⚠️  WARNING: fn registered in a savepoint is called after the outermost transaction is committed,
+// and is discarded if the savepoint is rolled back.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	scope, ok := scopeFromCtx(ctx)
+	if !ok {
+		fn(ctx)
+		return
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	scope.afterCommit = append(scope.afterCommit, fn)
+}
+
+// AfterRollback register fn which is called after the transaction (or savepoint) carried by ctx is rolled back.
+//
+// 💡 HINT: when ctx carries no transaction, fn is never called.
+func AfterRollback(ctx context.Context, fn func(ctx context.Context)) {
+	scope, ok := scopeFromCtx(ctx)
+	if !ok {
+		return
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	scope.afterRollback = append(scope.afterRollback, fn)
+}
+
+// TxFromCtx get the transaction carried by ctx.
+//
+// 💡 HINT: it is useful when you use gorm directly in Transaction.
+func TxFromCtx(ctx context.Context) (*gorm.DB, bool) {
+	scope, ok := scopeFromCtx(ctx)
+	if !ok {
+		return nil, false
+	}
+	return scope.tx, true
+}
+
+func scopeFromCtx(ctx context.Context) (*txScope, bool) {
+	scope, ok := ctx.Value(txCtxKey{}).(*txScope)
+	return scope, ok && scope != nil
+}
+
+// joinTxIfHas let db join the transaction carried by ctx if they are on the same database.
+func joinTxIfHas(ctx context.Context, db *gorm.DB) *gorm.DB {
+	scope, ok := scopeFromCtx(ctx)
+	if !ok || db.Error != nil || db.Config.ConnPool != scope.tx.Config.ConnPool {
+		return db
+	}
+	db.Statement.ConnPool = scope.tx.Statement.ConnPool
+	return db
+}
+
+// merge take over the callbacks of the released savepoint.
+func (scope *txScope) merge(child *txScope) {
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	scope.afterCommit = append(scope.afterCommit, child.afterCommit...)
+	scope.afterRollback = append(scope.afterRollback, child.afterRollback...)
+}
+
+func (scope *txScope) commit(ctx context.Context) {
+	for _, fn := range scope.afterCommit {
+		fn(ctx)
+	}
+}
+
+func (scope *txScope) rollback(ctx context.Context) {
+	for _, fn := range scope.afterRollback {
+		fn(ctx)
+	}
+}
@@ -0,0 +1,182 @@
+package gdal
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/dirac-lee/gdal/gutil/gerror"
+	"github.com/dirac-lee/gdal/gutil/gsql"
+	"gorm.io/gorm/clause"
+)
+
+// checkVersionField the field tagged by `gdal:"version"` must be integer
+func checkVersionField(structField reflect.StructField) error {
+	switch structField.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return nil
+	default:
+		return gerror.GDALErrorf("field (%s) tagged by version must be integer, but got %v", structField.Name, structField.Type)
+	}
+}
+
+// nextVersion the version following version
+func nextVersion(version any) any {
+	rv := reflect.ValueOf(version)
+	next := reflect.New(rv.Type()).Elem()
+	if rv.CanInt() {
+		next.SetInt(rv.Int() + 1)
+	} else {
+		next.SetUint(rv.Uint() + 1)
+	}
+	return next.Interface()
+}
+
+// versionExpr the predicate of records whose version is the expected
+func (meta *poMeta) versionExpr(expected any) clause.Expression {
+	return clause.Eq{Column: clause.Column{Name: meta.version}, Value: expected}
+}
+
+// versionIncrExpr the expression increasing version by 1
+//
+// 💡 HINT: the column is qualified by the current table, since it is ambiguous on conflict of upsert.
+func (meta *poMeta) versionIncrExpr() clause.Expression {
+	return clause.Expr{SQL: "? + 1", Vars: []any{clause.Column{Table: clause.CurrentTable, Name: meta.version}}}
+}
+
+// incrVersionIfHas increase the version by 1 along with attrs if PO has version column, so that the holders
+// of the previous version fail their checks.
+func (meta *poMeta) incrVersionIfHas(attrs map[string]any) map[string]any {
+	if len(meta.version) > 0 {
+		attrs[meta.version] = meta.versionIncrExpr()
+	}
+	return attrs
+}
+
+// update updates by Update struct, with version checked if PO has version column.
+//
+// 💡 HINT: if the version field of Update struct is set, it is taken as the expected version rather than
+// the new one, and VersionConflictError returns when no record matched.
+//
+// ⚠️  WARNING: if versionRequired, it fails when the version field is not set, otherwise, no version is checked.
+func (gdal *GDAL[PO, Where, Update]) update(ctx context.Context, where any, update *Update, versionRequired bool, options ...QueryOption) (int64, error) {
+	meta, err := getPOMeta[PO]()
+	if err != nil {
+		return 0, err
+	}
+	if len(meta.version) == 0 {
+		return gdal.extended().UpdateWithOptions(ctx, gdal.MakePO(), where, update, options...)
+	}
+
+	attrs, err := gsql.BuildSQLUpdate(update)
+	if err != nil {
+		return 0, err
+	}
+	if len(attrs) == 0 {
+		return 0, nil
+	}
+	expected, checked := attrs[meta.version]
+	if _, isExpr := expected.(clause.Expression); isExpr {
+		return 0, gerror.GDALErrorf("version column (%s) can not be updated by expression", meta.version)
+	}
+	if checked {
+		options = append(options[:len(options):len(options)], withExprs(meta.versionExpr(expected)))
+	} else if versionRequired {
+		return 0, gerror.GDALErrorf("expected version (%s) of model (%v) is required", meta.version, meta.structType)
+	}
+	guard, guarded, err := gsql.BuildSQLUpdateGuard(update)
+	if err != nil {
+		return 0, err
+	}
+	if guard != nil { // attrs lose the floors of Update struct
+		options = append(options[:len(options):len(options)], withGuard(guard, guarded))
+	}
+	attrs = meta.incrVersionIfHas(attrs)
+
+	rowsAffected, err := gdal.extended().UpdateWithOptions(ctx, gdal.MakePO(), where, attrs, options...)
+	if err != nil {
+		return 0, err
+	}
+	if checked && rowsAffected == 0 {
+		return 0, gerror.VersionConflictErr(meta.structType, expected)
+	}
+	return rowsAffected, nil
+}
+
+// saveWithVersion updates all the fields of po by primary key if the version of record is expected,
+// then increases the version of po.
+func (gdal *GDAL[PO, Where, Update]) saveWithVersion(ctx context.Context, meta *poMeta, po *PO, expected any) error {
+	where, err := gdal.pkWhere(meta.keyOf(po))
+	if err != nil {
+		return err
+	}
+
+	version := nextVersion(expected)
+	attrs := make(map[string]any, len(meta.columns))
+	for _, column := range meta.columns {
+		attrs[column] = meta.valueOf(po, column)
+	}
+	for _, column := range meta.primaryKeys {
+		delete(attrs, column)
+	}
+	attrs[meta.version] = version
+
+	rowsAffected, err := gdal.extended().UpdateWithOptions(ctx, gdal.MakePO(), where, attrs, withExprs(meta.versionExpr(expected)))
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return gerror.VersionConflictErr(meta.structType, expected)
+	}
+	reflect.ValueOf(po).Elem().Field(meta.column2Index[meta.version]).Set(reflect.ValueOf(version))
+	return nil
+}
+
+// UpdateWithRetry loads the record by primary key, mutates it by mutate, then saves it with version checked.
+// It reloads and retries at most maxRetries times on version conflict.
+//
+// 💡 HINT: the record must have version column tagged by `gdal:"version"`.
+//
+// ⚠️  WARNING: mutate may be called more than once, so it should be free of side effects.
+//
+// 🚀 example:
+//
+//	err := gdal.UpdateWithRetry(ctx, 110, 3, func(po *model.User) error {
+//		po.Balance += 10
+//		return nil
+//	})
+func (gdal *GDAL[PO, Where, Update]) UpdateWithRetry(ctx context.Context, id any, maxRetries int, mutate func(po *PO) error) error {
+	meta, err := getPOMeta[PO]()
+	if err != nil {
+		return err
+	}
+	if len(meta.version) == 0 {
+		return gerror.GDALErrorf("can not update model (%v) with retry without version column", meta.structType)
+	}
+	where, err := gdal.pkWhere(id)
+	if err != nil {
+		return err
+	}
+	options, err := gdal.scopeIfSoftDelete(nil) // the soft-deleted records are not to be updated.
+	if err != nil {
+		return err
+	}
+
+	for retries := 0; ; retries++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var po PO
+		if err := gdal.DAL.First(ctx, &po, where, options...); err != nil {
+			return err
+		}
+		expected := meta.valueOf(&po, meta.version)
+		if err := mutate(&po); err != nil {
+			return err
+		}
+		err := gdal.saveWithVersion(ctx, meta, &po, expected)
+		if !gerror.IsVersionConflictErr(err) || retries >= maxRetries {
+			return err
+		}
+	}
+}